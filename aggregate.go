@@ -0,0 +1,100 @@
+package norm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Aggregate is an aggregate select item created by [Agg]. It is an
+// [Option] for [Model.Select] and [Model.Pointers] and carries the scan
+// target for its output column.
+type Aggregate struct {
+	fn     string
+	field  string
+	alias  string
+	target any
+	result any
+}
+
+// Agg creates an aggregate select item fn(field) AS alias. fn is the SQL
+// function name (count, sum, avg, min, max, ...), field is a model field in
+// any name format or "*". If alias is empty it defaults to fn_column, or fn
+// for "*". The alias can be used in [Order].
+//
+// [Model.Pointers] adds a scan target for every aggregate: the one set with
+// [Aggregate.Into], otherwise an internal value read with [Aggregate.Result].
+//
+//	var total float64
+//	sum := norm.Agg("sum", "Total", "total_sum").Into(&total)
+//	sql, args, _ := m.Select(norm.GroupBy("UserId"), sum, norm.Order("total_sum DESC"))
+//	// "SELECT user_id, sum(total) AS total_sum FROM orders GROUP BY user_id ORDER BY total_sum DESC"
+//	err := rows.Scan(m.Pointers(norm.GroupBy("UserId"), sum)...)
+func Agg(fn, field, alias string) *Aggregate {
+	return &Aggregate{fn: strings.ToLower(strings.TrimSpace(fn)), field: strings.TrimSpace(field), alias: alias}
+}
+
+// Into sets the scan target for the aggregate output and returns a.
+func (a *Aggregate) Into(target any) *Aggregate {
+	a.target = target
+	return a
+}
+
+// Result returns the value scanned into the internal target when no
+// target was set with [Aggregate.Into].
+func (a *Aggregate) Result() any {
+	return a.result
+}
+
+func (a *Aggregate) Type() OptionType { return AggregateOption }
+func (a *Aggregate) Value() any       { return a }
+
+// pointer returns the scan target of the aggregate.
+func (a *Aggregate) pointer() any {
+	if a.target != nil {
+		return a.target
+	}
+	return &a.result
+}
+
+// aggSQL renders an aggregate select item and returns it with its alias,
+// unquoted; the rendered alias is quoted like a column name.
+// Must be called under m.mut.RLock.
+func (m *modelMeta) aggSQL(a *Aggregate, prefix string) (string, string, error) {
+	if !isValidIdentifier(a.fn) {
+		return "", "", fmt.Errorf("Agg: invalid function %q", a.fn)
+	}
+
+	arg := "*"
+	alias := a.alias
+	if a.field != "*" {
+		f, ok := m.fieldByAnyName[a.field]
+		if !ok {
+			return "", "", m.unknownFieldError("Agg", a.field)
+		}
+		arg = prefix + m.ident(f.dbName)
+		if alias == "" {
+			alias = a.fn + "_" + f.dbName
+		}
+	}
+	if alias == "" {
+		alias = a.fn
+	}
+	if !isValidIdentifier(alias) {
+		return "", "", fmt.Errorf("Agg: invalid alias %q", alias)
+	}
+
+	return fmt.Sprintf("%s(%s) AS %s", a.fn, arg, m.ident(alias)), alias, nil
+}
+
+// aggAliases returns the aliases of the aggregates for use in ORDER BY.
+// Invalid aggregates are skipped; they are reported when rendered.
+// Must be called under m.mut.RLock.
+func (m *modelMeta) aggAliases(aggs []*Aggregate) []string {
+	res := make([]string, 0, len(aggs))
+	for _, a := range aggs {
+		if _, alias, err := m.aggSQL(a, ""); err == nil {
+			res = append(res, alias)
+		}
+	}
+	return res
+}
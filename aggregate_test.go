@@ -0,0 +1,119 @@
+package norm
+
+import (
+	"errors"
+	"testing"
+)
+
+type AggOrder struct {
+	Id     int `norm:"pk"`
+	UserId int
+	Status string
+	Total  float64
+}
+
+func TestGroupByAgg(t *testing.T) {
+	n := NewNorm(nil)
+	order := AggOrder{}
+	m, _ := n.M(&order)
+
+	t.Run("group by with aggregates and having", func(t *testing.T) {
+		sql, args, err := m.Select(
+			Where("status <> ?", "cancelled"),
+			GroupBy("UserId"),
+			Agg("count", "*", "n"),
+			Agg("sum", "Total", "total_sum"),
+			Having("count(*) > ?", 5),
+			Order("total_sum DESC, UserId"),
+			Limit(10),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT user_id, count(*) AS n, sum(total) AS total_sum FROM agg_order WHERE status <> $1 GROUP BY user_id HAVING count(*) > $2 ORDER BY total_sum DESC, user_id ASC LIMIT 10"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 2 || args[0] != "cancelled" || args[1] != 5 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("aggregates only", func(t *testing.T) {
+		sql, _, err := m.Select(Agg("max", "total", ""), Agg("COUNT", "*", ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT max(total) AS max_total, count(*) AS count FROM agg_order"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("explicit fields", func(t *testing.T) {
+		sql, _, err := m.Select(Fields("status,user_id"), GroupBy("Status, UserId"), Agg("avg", "Total", "avg_total"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT user_id, status, avg(total) AS avg_total FROM agg_order GROUP BY status, user_id"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("scan targets", func(t *testing.T) {
+		var sum float64
+		count := Agg("count", "*", "n")
+		total := Agg("sum", "Total", "total_sum").Into(&sum)
+		ptrs := m.Pointers(GroupBy("UserId"), count, total)
+		if len(ptrs) != 3 {
+			t.Fatalf("expected 3 pointers, got %d", len(ptrs))
+		}
+		if ptrs[0] != &order.UserId || ptrs[2] != &sum {
+			t.Errorf("unexpected pointers: %v", ptrs)
+		}
+		*(ptrs[1].(*any)) = int64(7)
+		if count.Result() != int64(7) {
+			t.Errorf("got result %v", count.Result())
+		}
+	})
+
+	t.Run("unknown group by field", func(t *testing.T) {
+		_, _, err := m.Select(GroupBy("Nope"))
+		if !errors.Is(err, ErrUnknownField) {
+			t.Errorf("expected ErrUnknownField, got %v", err)
+		}
+	})
+
+	t.Run("invalid aggregate", func(t *testing.T) {
+		tests := []*Aggregate{
+			Agg("sum(1); drop", "Total", "x"),
+			Agg("sum", "Nope", "x"),
+			Agg("sum", "Total", "x y"),
+		}
+		for _, a := range tests {
+			if _, _, err := m.Select(a); err == nil {
+				t.Errorf("expected error for %+v", a)
+			}
+		}
+	})
+}
+
+func TestAggAliasQuoting(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&User{})
+
+	sql, _, err := m.Select(
+		GroupBy("Name"),
+		Agg("count", "*", "order"),
+		Agg("max", "UserId", ""),
+		Order("order DESC, max_userId"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `SELECT name, count(*) AS "order", max("userId") AS "max_userId" FROM "user" GROUP BY name ORDER BY "order" DESC, "max_userId" ASC`
+	if sql != want {
+		t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+	}
+}
@@ -0,0 +1,174 @@
+package norm
+
+import (
+	"testing"
+	"time"
+)
+
+type AutoTimeStruct struct {
+	Id        int `norm:"pk"`
+	Name      string
+	CreatedAt time.Time  `norm:"autoCreateTime"`
+	UpdatedAt *time.Time `norm:"autoUpdateTime"`
+}
+
+func TestAutoTimeSQLNow(t *testing.T) {
+	n := NewNorm(&Config{})
+	m, _ := n.M(&AutoTimeStruct{Id: 1, Name: "Alice"})
+
+	t.Run("insert", func(t *testing.T) {
+		sql, vals, err := m.Insert(Exclude("id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO auto_time_struct (name, created_at, updated_at) VALUES ($1, now(), now())"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(vals) != 1 || vals[0] != "Alice" {
+			t.Errorf("unexpected vals: %v", vals)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		sql, vals, err := m.Update(Exclude("id"), Where("id = ?", 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "UPDATE auto_time_struct SET name=$1, updated_at=now() WHERE id = $2"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(vals) != 2 || vals[1] != 1 {
+			t.Errorf("unexpected vals: %v", vals)
+		}
+	})
+
+	t.Run("update with fields still sets updated column", func(t *testing.T) {
+		sql, _, _ := m.UpdateByPK(Fields("name"))
+		want := "UPDATE auto_time_struct SET name=$1, updated_at=now() WHERE id=$2"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("update created column when listed explicitly", func(t *testing.T) {
+		sql, _, _ := m.UpdateByPK(Fields("created_at"), Exclude("updated_at"))
+		want := "UPDATE auto_time_struct SET created_at=$1 WHERE id=$2"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("upsert keeps created column", func(t *testing.T) {
+		sql, _, _ := m.Insert(OnConflict().DoUpdate())
+		want := "INSERT INTO auto_time_struct (id, name, created_at, updated_at) VALUES ($1, $2, now(), now())" +
+			" ON CONFLICT (id) DO UPDATE SET name=EXCLUDED.name, updated_at=EXCLUDED.updated_at"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+}
+
+func TestAutoTimeClock(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	n := NewNorm(&Config{NowFunc: func() time.Time { return now }})
+	m, _ := n.M(&AutoTimeStruct{Id: 1, Name: "Alice"})
+
+	t.Run("insert", func(t *testing.T) {
+		sql, vals, err := m.Insert(Exclude("id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO auto_time_struct (name, created_at, updated_at) VALUES ($1, $2, $3)"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(vals) != 3 || vals[1] != now || vals[2] != now {
+			t.Errorf("unexpected vals: %v", vals)
+		}
+	})
+
+	t.Run("insert many", func(t *testing.T) {
+		rows := []AutoTimeStruct{{Name: "A"}, {Name: "B"}}
+		queries, err := m.InsertMany(rows, Exclude("id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO auto_time_struct (name, created_at, updated_at) VALUES ($1, $2, $3), ($4, $5, $6)"
+		if queries[0].SQL != want {
+			t.Errorf("got %q", queries[0].SQL)
+		}
+		if queries[0].Args[4] != now {
+			t.Errorf("unexpected args: %v", queries[0].Args)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		sql, vals, _ := m.UpdateByPK()
+		want := "UPDATE auto_time_struct SET name=$1, updated_at=$2 WHERE id=$3"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(vals) != 3 || vals[1] != now || vals[2] != 1 {
+			t.Errorf("unexpected vals: %v", vals)
+		}
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		ms, _ := n.M(&SoftUser{Id: 1})
+		sql, vals, _ := ms.DeleteByPK()
+		want := "UPDATE soft_user SET deleted_at=$1 WHERE id=$2 AND deleted_at IS NULL"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(vals) != 2 || vals[0] != now || vals[1] != 1 {
+			t.Errorf("unexpected vals: %v", vals)
+		}
+	})
+}
+
+func TestAutoTimeClockOncePerStatement(t *testing.T) {
+	calls := 0
+	n := NewNorm(&Config{NowFunc: func() time.Time {
+		calls++
+		return time.Unix(int64(calls), 0)
+	}})
+	m, _ := n.M(&AutoTimeStruct{Name: "Alice"})
+
+	_, vals, err := m.Insert(Exclude("id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || vals[1] != vals[2] {
+		t.Errorf("Insert: %d calls, vals %v", calls, vals)
+	}
+
+	calls = 0
+	queries, err := m.InsertMany([]AutoTimeStruct{{Name: "A"}, {Name: "B"}}, Exclude("id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := queries[0].Args
+	if calls != 1 || args[1] != args[2] || args[1] != args[4] || args[1] != args[5] {
+		t.Errorf("InsertMany: %d calls, args %v", calls, args)
+	}
+}
+
+func TestAutoTimeUpdateChanged(t *testing.T) {
+	n := NewNorm(&Config{})
+	obj := &AutoTimeStruct{Id: 1, Name: "Alice"}
+	m, _ := n.M(obj)
+	_ = m.Snapshot()
+
+	obj.Name = "Bob"
+
+	sql, _, err := m.UpdateChanged(Where("id = ?", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "UPDATE auto_time_struct SET name=$1, updated_at=now() WHERE id = $2"
+	if sql != want {
+		t.Errorf("got %q", sql)
+	}
+}
@@ -0,0 +1,147 @@
+package norm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Compound is a fluent builder combining SELECT queries with UNION,
+// UNION ALL, INTERSECT or EXCEPT. Create it with [Union], [UnionAll],
+// [Intersect] or [Except]. Each query is parenthesized, so it can have its
+// own ORDER BY and LIMIT; [Compound.Order], [Compound.Limit] and
+// [Compound.Offset] apply to the combined result.
+//
+//	sql, args, _ := norm.UnionAll(
+//	    mPost.SubSelect(norm.Fields("id,created_at"), norm.Where("author_id = ?", uid)),
+//	    mComment.SubSelect(norm.Fields("id,created_at"), norm.Where("author_id = ?", uid)),
+//	).Order("created_at DESC").Limit(20).Select()
+//	// "(SELECT id, created_at FROM posts WHERE author_id = $1) UNION ALL
+//	//  (SELECT id, created_at FROM comments WHERE author_id = $2) ORDER BY created_at DESC LIMIT 20"
+type Compound struct {
+	op      string
+	queries []Query
+	orderBy string
+	limit   int
+	offset  int
+}
+
+// Union combines queries with UNION, removing duplicate rows.
+func Union(queries ...Query) *Compound {
+	return &Compound{op: "UNION", queries: queries}
+}
+
+// UnionAll combines queries with UNION ALL, keeping duplicate rows.
+func UnionAll(queries ...Query) *Compound {
+	return &Compound{op: "UNION ALL", queries: queries}
+}
+
+// Intersect combines queries with INTERSECT.
+func Intersect(queries ...Query) *Compound {
+	return &Compound{op: "INTERSECT", queries: queries}
+}
+
+// Except combines queries with EXCEPT: rows of the first query that are
+// not returned by the others.
+func Except(queries ...Query) *Compound {
+	return &Compound{op: "EXCEPT", queries: queries}
+}
+
+// Order sets the ORDER BY clause of the combined result. Use raw SQL with
+// output column names.
+//
+//	c.Order("created_at DESC, id DESC")
+func (c *Compound) Order(orderBy string) *Compound {
+	c.orderBy = orderBy
+	return c
+}
+
+// Limit sets the LIMIT value of the combined result.
+func (c *Compound) Limit(limit int) *Compound {
+	c.limit = limit
+	return c
+}
+
+// Offset sets the OFFSET value of the combined result.
+func (c *Compound) Offset(offset int) *Compound {
+	c.offset = offset
+	return c
+}
+
+// Select builds the combined query in the dialect of the queries. The
+// binds of every query are renumbered in order. Each query is rendered
+// with [Dialect.CompoundMember]: parenthesized, or bare in [SQLite], where
+// a query cannot have its own ORDER BY or LIMIT. Returns an error if a
+// query failed to build, if queries were built in different dialects or if
+// the queries built with [Model.SubSelect] or [Join.SubSelect] return
+// different numbers of columns.
+func (c *Compound) Select() (string, []any, error) {
+	if len(c.queries) == 0 {
+		return "", nil, errors.New("Select: no queries to combine")
+	}
+
+	cols := 0
+	for i, q := range c.queries {
+		if q.err != nil {
+			return "", nil, fmt.Errorf("Select: query %d: %w", i+1, q.err)
+		}
+		if q.cols == 0 {
+			continue
+		}
+		if cols != 0 && q.cols != cols {
+			return "", nil, fmt.Errorf("Select: %s query %d returns %d columns, expected %d", c.op, i+1, q.cols, cols)
+		}
+		cols = q.cols
+	}
+
+	var d Dialect
+	for i, q := range c.queries {
+		if q.dialect == nil {
+			continue
+		}
+		if d != nil && q.dialect != d {
+			return "", nil, fmt.Errorf("Select: %s query %d was built for a different dialect", c.op, i+1)
+		}
+		d = q.dialect
+	}
+	if d == nil {
+		d = Postgres
+	}
+	b := newDialectBinder(d)
+
+	parts := make([]string, len(c.queries))
+	for i, q := range c.queries {
+		part := b.embed(q)
+		if q.compound && bareMembers(d) {
+			// keep the precedence of a nested compound without parentheses
+			part = "SELECT * FROM (" + part + ")"
+		}
+		parts[i] = d.CompoundMember(part)
+	}
+	sql := strings.Join(parts, " "+c.op+" ")
+
+	if c.orderBy != "" {
+		sql += " ORDER BY " + c.orderBy
+	}
+	if lo := b.dialect.LimitOffset(c.limit, c.offset); lo != "" {
+		sql += " " + lo
+	}
+
+	return sql, b.args, nil
+}
+
+// SubSelect builds the combined query for use inside another statement,
+// e.g. as a part of another [Compound] or with [With].
+func (c *Compound) SubSelect() Query {
+	q := NewQuery(c.Select())
+	q.compound = true
+	for _, sub := range c.queries {
+		if q.dialect == nil {
+			q.dialect = sub.dialect
+		}
+		if q.cols == 0 {
+			q.cols = sub.cols
+		}
+	}
+	return q
+}
@@ -0,0 +1,110 @@
+package norm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompound(t *testing.T) {
+	mUser, mOrder, _ := setupJoinModels(t)
+
+	t.Run("union all with outer order and limit", func(t *testing.T) {
+		sql, args, err := UnionAll(
+			mUser.SubSelect(Fields("id,name"), Where("name = ?", "Alice")),
+			mOrder.SubSelect(Fields("id,user_id"), Where("total > ?", 10), Order("Id DESC"), Limit(5)),
+		).Order("id DESC").Limit(20).Offset(40).Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "(SELECT id, name FROM join_user WHERE name = $1) UNION ALL (SELECT id, user_id FROM join_order WHERE total > $2 ORDER BY id DESC LIMIT 5) ORDER BY id DESC LIMIT 20 OFFSET 40"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 2 || args[0] != "Alice" || args[1] != 10 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("operators", func(t *testing.T) {
+		a := mUser.SubSelect(Fields("id"))
+		b := mOrder.SubSelect(Fields("user_id"))
+		tests := []struct {
+			c    *Compound
+			want string
+		}{
+			{Union(a, b), "(SELECT id FROM join_user) UNION (SELECT user_id FROM join_order)"},
+			{Intersect(a, b), "(SELECT id FROM join_user) INTERSECT (SELECT user_id FROM join_order)"},
+			{Except(a, b), "(SELECT id FROM join_user) EXCEPT (SELECT user_id FROM join_order)"},
+		}
+		for _, tt := range tests {
+			sql, _, err := tt.c.Select()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.want {
+				t.Errorf("got %q", sql)
+			}
+		}
+	})
+
+	t.Run("join and nested compound", func(t *testing.T) {
+		nested := Except(mOrder.SubSelect(Fields("id"), Where("total < ?", 0)), mOrder.SubSelect(Fields("id")))
+		sql, args, err := Union(
+			NewJoin(mUser).Where("join_user.id = ?", 1).SubSelect(),
+			mUser.SubSelect(Where("id = ?", 2)),
+		).Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "(SELECT join_user.id, join_user.name, join_user.email FROM join_user WHERE join_user.id = $1) UNION (SELECT id, name, email FROM join_user WHERE id = $2)"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 2 {
+			t.Errorf("unexpected args: %v", args)
+		}
+
+		sql, args, err = UnionAll(mOrder.SubSelect(Fields("id"), Where("total = ?", 5)), nested.SubSelect()).Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = "(SELECT id FROM join_order WHERE total = $1) UNION ALL ((SELECT id FROM join_order WHERE total < $2) EXCEPT (SELECT id FROM join_order))"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 2 || args[0] != 5 || args[1] != 0 {
+			t.Errorf("unexpected args: %v", args)
+		}
+
+		j := NewJoin(mUser).Inner(mOrder, "join_order.user_id = join_user.id").Where("join_order.total > ?", 1)
+		if _, _, err := Union(j.SubSelect(), mUser.SubSelect()).Select(); err == nil {
+			t.Error("expected column count error for join")
+		}
+	})
+
+	t.Run("column count mismatch", func(t *testing.T) {
+		_, _, err := Union(mUser.SubSelect(Fields("id")), mOrder.SubSelect(Fields("id,user_id"))).Select()
+		if err == nil {
+			t.Error("expected error")
+		}
+
+		// hand-written queries are not checked
+		_, _, err = Union(mUser.SubSelect(Fields("id")), Query{SQL: "SELECT 1, 2"}).Select()
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, _, err := Union().Select(); err == nil {
+			t.Error("expected error for no queries")
+		}
+
+		n := NewNorm(&Config{Strict: true})
+		m, _ := n.M(&JoinOrder{})
+		_, _, err := Union(mUser.SubSelect(Fields("id")), m.SubSelect(Fields("nope"))).Select()
+		if !errors.Is(err, ErrUnknownField) {
+			t.Errorf("expected ErrUnknownField, got %v", err)
+		}
+	})
+}
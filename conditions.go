@@ -12,7 +12,8 @@ type Cond interface {
 	isCond()
 }
 
-// condition represents a comparison condition (=, >, >=, <, <=, !=, LIKE).
+// condition represents a binary condition with a single bind value
+// (=, >, >=, <, <=, !=, LIKE, ILIKE, IS DISTINCT FROM, ~, ...).
 type condition struct {
 	field string
 	op    string
@@ -21,14 +22,32 @@ type condition struct {
 
 func (c condition) isCond() {}
 
-// condIn represents an IN (...) condition.
+// condIn represents an IN (...) or NOT IN (...) condition.
 type condIn struct {
 	field  string
 	values []any
+	not    bool
 }
 
 func (c condIn) isCond() {}
 
+// condAny represents field = ANY(array) with the array bound as one value.
+type condAny struct {
+	field string
+	array any
+}
+
+func (c condAny) isCond() {}
+
+// condBetween represents a [NOT] BETWEEN ... AND ... condition.
+type condBetween struct {
+	field    string
+	from, to any
+	not      bool
+}
+
+func (c condBetween) isCond() {}
+
 // condIsNull represents IS NULL / IS NOT NULL.
 type condIsNull struct {
 	field  string
@@ -37,6 +56,21 @@ type condIsNull struct {
 
 func (c condIsNull) isCond() {}
 
+// condGroup represents conditions joined with AND or OR.
+type condGroup struct {
+	op    string
+	conds []Cond
+}
+
+func (c condGroup) isCond() {}
+
+// condNot represents a negated condition.
+type condNot struct {
+	cond Cond
+}
+
+func (c condNot) isCond() {}
+
 // prefixOption also implements Cond so Prefix() works in BuildConditions.
 func (o prefixOption) isCond() {}
 
@@ -89,6 +123,57 @@ func Like(field string, value any) Cond {
 	return condition{field: field, op: "LIKE", value: value}
 }
 
+// NotLike creates a NOT LIKE condition: field NOT LIKE value.
+//
+//	norm.NotLike("name", "%test%")  // name NOT LIKE $1
+func NotLike(field string, value any) Cond {
+	return condition{field: field, op: "NOT LIKE", value: value}
+}
+
+// ILike creates a case-insensitive ILIKE condition: field ILIKE value.
+//
+//	norm.ILike("name", "%john%")  // name ILIKE $1
+func ILike(field string, value any) Cond {
+	return condition{field: field, op: "ILIKE", value: value}
+}
+
+// Regex creates a case-sensitive POSIX regular expression match: field ~ pattern.
+//
+//	norm.Regex("email", "@example\\.com$")  // email ~ $1
+func Regex(field string, pattern any) Cond {
+	return condition{field: field, op: "~", value: pattern}
+}
+
+// IRegex creates a case-insensitive POSIX regular expression match: field ~* pattern.
+//
+//	norm.IRegex("name", "^jo")  // name ~* $1
+func IRegex(field string, pattern any) Cond {
+	return condition{field: field, op: "~*", value: pattern}
+}
+
+// IsDistinctFrom creates a NULL-safe not-equal condition:
+// field IS DISTINCT FROM value. Unlike [Ne] it is true when exactly one
+// side is NULL.
+//
+//	norm.IsDistinctFrom("email", nil)  // email IS DISTINCT FROM $1
+func IsDistinctFrom(field string, value any) Cond {
+	return condition{field: field, op: "IS DISTINCT FROM", value: value}
+}
+
+// Between creates an inclusive range condition: field BETWEEN from AND to.
+//
+//	norm.Between("age", 18, 65)  // age BETWEEN $1 AND $2
+func Between(field string, from, to any) Cond {
+	return condBetween{field: field, from: from, to: to}
+}
+
+// NotBetween creates a negated range condition: field NOT BETWEEN from AND to.
+//
+//	norm.NotBetween("age", 18, 65)  // age NOT BETWEEN $1 AND $2
+func NotBetween(field string, from, to any) Cond {
+	return condBetween{field: field, from: from, to: to, not: true}
+}
+
 // IsNull creates an IS NULL or IS NOT NULL condition.
 //
 //	norm.IsNull("email", true)   // email IS NULL
@@ -97,14 +182,57 @@ func IsNull(field string, isNull bool) Cond {
 	return condIsNull{field: field, isNull: isNull}
 }
 
-// In creates an IN (...) condition.
+// In creates an IN (...) condition. An empty list renders as FALSE, since
+// "IN ()" is not valid SQL.
 //
 //	norm.In("id", 1, 2, 3)          // id IN ($1, $2, $3)
 //	norm.In("name", "Alice", "Bob") // name IN ($1, $2)
+//	norm.In("id")                   // FALSE
 func In(field string, values ...any) Cond {
 	return condIn{field: field, values: values}
 }
 
+// NotIn creates a NOT IN (...) condition. An empty list renders as TRUE.
+//
+//	norm.NotIn("status", "banned", "deleted")  // status NOT IN ($1, $2)
+func NotIn(field string, values ...any) Cond {
+	return condIn{field: field, values: values, not: true}
+}
+
+// EqAny creates a field = ANY(array) condition. The whole slice is passed
+// as a single bind value, so the statement text does not depend on the
+// number of elements and can be cached by the driver.
+//
+//	norm.EqAny("id", []int{1, 2, 3})  // id = ANY($1)
+func EqAny(field string, array any) Cond {
+	return condAny{field: field, array: array}
+}
+
+// And groups conditions with AND. Useful inside [Or]; top-level conditions
+// of [BuildConditions] are already meant to be joined with AND.
+//
+//	norm.Or(norm.And(norm.Eq("a", 1), norm.Eq("b", 2)), norm.Eq("c", 3))
+//	// ((a=$1 AND b=$2) OR c=$3)
+func And(conds ...Cond) Cond {
+	return condGroup{op: "AND", conds: conds}
+}
+
+// Or groups conditions with OR. The group is wrapped in parentheses.
+// An empty Or renders FALSE, an empty [And] renders TRUE, so a list built
+// at runtime that turns out empty matches no rows instead of all of them.
+//
+//	norm.Or(norm.Eq("a", 1), norm.Eq("b", 2))  // (a=$1 OR b=$2)
+func Or(conds ...Cond) Cond {
+	return condGroup{op: "OR", conds: conds}
+}
+
+// Not negates a condition.
+//
+//	norm.Not(norm.IsNull("c", true))  // NOT (c IS NULL)
+func Not(cond Cond) Cond {
+	return condNot{cond: cond}
+}
+
 // parseFieldParts splits a field reference into (prefix, fieldName, suffix).
 //
 //	"name"          → ("", "name", "")
@@ -135,79 +263,299 @@ func parseFieldParts(field string) (prefix, name, suffix string) {
 // in the output SQL, and the field is looked up without it.
 // Use [Prefix] to add the same prefix to all conditions at once.
 // Use "field->>jsonKey" for JSON field access.
+// Use [Or], [And] and [Not] to build nested boolean expressions; bind
+// numbers run sequentially through the whole tree.
+//
+// Conditions on unknown fields are skipped; in [Config.Strict] mode
+// BuildConditions panics instead, see also [Model.BuildConditionsE].
+// It also panics if a subquery passed to [InSub] or [Exists] failed to build.
+//
+// For models with a softdelete field a "deleted_at IS NULL" condition is
+// appended; pass [WithDeleted] or [OnlyDeleted] to change that.
 //
 //	conds, vals := m.BuildConditions(
 //	    norm.Eq("u.name", "John"),
 //	    norm.Gte("u.age", 18),
 //	    norm.In("o.id", 1, 2, 3),
 //	    norm.IsNull("u.deleted_at", true),
+//	    norm.Or(norm.Eq("u.role", "admin"), norm.Not(norm.IsNull("u.invited_by", true))),
 //	)
 func (m *modelMeta) BuildConditions(conds ...Cond) ([]string, []any) {
+	if err := subqueryErr(conds); err != nil {
+		panic("BuildConditions: " + err.Error())
+	}
+	if err := condsSupported("BuildConditions", conds, m.config.Dialect); err != nil {
+		panic(err.Error())
+	}
+	conditions, args, err := m.buildConditions("BuildConditions", conds)
+	if err != nil && m.config.Strict {
+		panic(err.Error())
+	}
+	return conditions, args
+}
+
+// BuildConditionsE is like [Model.BuildConditions] but returns an error
+// wrapping [ErrUnknownField] instead of skipping conditions on unknown
+// fields, regardless of [Config.Strict].
+//
+//	conds, vals, err := m.BuildConditionsE(norm.Eq("nmae", "John"))
+//	// err: BuildConditionsE: unknown field "nmae" in model "users"
+func (m *modelMeta) BuildConditionsE(conds ...Cond) ([]string, []any, error) {
+	if err := condsSupported("BuildConditionsE", conds, m.config.Dialect); err != nil {
+		return nil, nil, err
+	}
+	conditions, args, err := m.buildConditions("BuildConditionsE", conds)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conditions, args, nil
+}
+
+// buildConditions renders conds for BuildConditions and BuildConditionsE.
+// Conditions on unknown fields are skipped; the first unknown name is
+// reported in the returned error.
+func (m *modelMeta) buildConditions(method string, conds []Cond) ([]string, []any, error) {
 	var globalPrefix string
+	scope := excludeDeleted
 	for _, c := range conds {
-		if p, ok := c.(prefixOption); ok {
-			globalPrefix = string(p)
+		switch v := c.(type) {
+		case prefixOption:
+			globalPrefix = string(v)
+		case deletedScope:
+			scope = v
 		}
 	}
 
-	var conditions []string
-	var values []any
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	b := m.newBinder()
+	conditions, unknown := renderConds(conds, m.condColumn(globalPrefix), b)
 
+	if sd := m.softDeleteWhere(globalPrefix, scope); sd != nil {
+		conditions = append(conditions, sd.template)
+	}
+
+	err := subqueryErr(conds)
+	if err == nil && unknown != "" {
+		err = m.unknownFieldError(method, unknown)
+	}
+	return conditions, b.args, err
+}
+
+// condsSupported reports the first condition in conds, including nested
+// ones, that uses a [Feature] d does not support.
+func condsSupported(method string, conds []Cond, d Dialect) error {
 	for _, c := range conds {
+		var err error
 		switch v := c.(type) {
 		case condition:
-			p, fieldName, suffix := parseFieldParts(v.field)
-			f, ok := m.fieldByAnyName[fieldName]
-			if !ok {
-				continue
-			}
-			values = append(values, v.value)
-			pfx := p
-			if pfx == "" {
-				pfx = globalPrefix
-			}
-			dbField := pfx + f.dbName + suffix
-			if v.op == "=" {
-				conditions = append(conditions, fmt.Sprintf("%s=$%d", dbField, len(values)))
-			} else {
-				conditions = append(conditions, fmt.Sprintf("%s %s $%d", dbField, v.op, len(values)))
+			switch v.op {
+			case "ILIKE":
+				err = unsupported(method, d, FeatureILike)
+			case "~", "~*":
+				err = unsupported(method, d, FeatureRegex)
+			case "IS DISTINCT FROM":
+				err = unsupported(method, d, FeatureIsDistinctFrom)
 			}
+		case condAny:
+			err = unsupported(method, d, FeatureArrays)
+		case condGroup:
+			err = condsSupported(method, v.conds, d)
+		case condNot:
+			err = condsSupported(method, []Cond{v.cond}, d)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		case condIn:
-			p, fieldName, suffix := parseFieldParts(v.field)
-			f, ok := m.fieldByAnyName[fieldName]
-			if !ok {
-				continue
+// renderConds renders each condition that produces SQL, taking placeholders
+// from b. Returns the rendered conditions and the first unknown field
+// reference, if any.
+func renderConds(conds []Cond, column columnFunc, b *binder) ([]string, string) {
+	var unknown string
+	tracked := func(field string) (string, bool) {
+		col, ok := column(field)
+		if !ok && unknown == "" {
+			unknown = field
+		}
+		return col, ok
+	}
+
+	var res []string
+	for _, c := range conds {
+		if sql, ok := renderCond(c, tracked, b); ok {
+			res = append(res, sql)
+		}
+	}
+	return res, unknown
+}
+
+// condsWhere returns a predicate rendering conds joined with AND, or nil
+// if there are no conditions. If onUnknown is not nil, it is called with
+// the first unknown field reference and its error is returned.
+func condsWhere(conds []Cond, column columnFunc, onUnknown func(field string) error) *whereOption {
+	if len(conds) == 0 {
+		return nil
+	}
+	return &whereOption{
+		grouped: true,
+		build: func(b *binder) (string, error) {
+			if err := subqueryErr(conds); err != nil {
+				return "", err
 			}
-			pfx := p
-			if pfx == "" {
-				pfx = globalPrefix
+			if err := condsSupported("WhereConds", conds, b.dialect); err != nil {
+				return "", err
 			}
-			placeholders := make([]string, len(v.values))
-			for i, val := range v.values {
-				values = append(values, val)
-				placeholders[i] = fmt.Sprintf("$%d", len(values))
+			parts, unknown := renderConds(conds, column, b)
+			if unknown != "" && onUnknown != nil {
+				return "", onUnknown(unknown)
 			}
-			conditions = append(conditions, fmt.Sprintf("%s%s%s IN (%s)",
-				pfx, f.dbName, suffix, strings.Join(placeholders, ", ")))
-
-		case condIsNull:
-			p, fieldName, suffix := parseFieldParts(v.field)
-			f, ok := m.fieldByAnyName[fieldName]
-			if !ok {
-				continue
+			return strings.Join(parts, " AND "), nil
+		},
+	}
+}
+
+// whereConds returns the [WhereConds] predicate for this model.
+// Unknown fields are an error in [Config.Strict] mode.
+// Must be called under m.mut.RLock.
+func (m *modelMeta) whereConds(method string, co ComposedOptions, prefix string) *whereOption {
+	var onUnknown func(string) error
+	if m.config.Strict {
+		onUnknown = func(field string) error { return m.unknownFieldError(method, field) }
+	}
+	return condsWhere(co.WhereConds, m.condColumn(prefix), onUnknown)
+}
+
+// columnFunc maps a condition field reference to a SQL column expression.
+// Returns false if the field is unknown.
+type columnFunc func(field string) (string, bool)
+
+// condColumn returns a columnFunc that resolves fields of this model.
+// globalPrefix is used when the reference has no dot prefix of its own.
+// Must be called under m.mut.RLock.
+func (m *modelMeta) condColumn(globalPrefix string) columnFunc {
+	return func(field string) (string, bool) {
+		p, fieldName, suffix := parseFieldParts(field)
+		f, ok := m.fieldByAnyName[fieldName]
+		if !ok {
+			return "", false
+		}
+		if p == "" {
+			p = globalPrefix
+		}
+		return p + m.ident(f.dbName) + suffix, true
+	}
+}
+
+// renderCond renders a single condition, taking placeholders from b.
+// Returns false if the condition produces no SQL: an unknown field, an
+// option such as [Prefix], or a group whose members all render to nothing.
+func renderCond(c Cond, column columnFunc, b *binder) (string, bool) {
+	switch v := c.(type) {
+	case condition:
+		col, ok := column(v.field)
+		if !ok {
+			return "", false
+		}
+		if v.op == "=" {
+			return col + "=" + b.bind(v.value), true
+		}
+		return col + " " + v.op + " " + b.bind(v.value), true
+
+	case condIn:
+		col, ok := column(v.field)
+		if !ok {
+			return "", false
+		}
+		if len(v.values) == 0 {
+			if v.not {
+				return "TRUE", true
 			}
-			pfx := p
-			if pfx == "" {
-				pfx = globalPrefix
+			return "FALSE", true
+		}
+		placeholders := make([]string, len(v.values))
+		for i, val := range v.values {
+			placeholders[i] = b.bind(val)
+		}
+		op := "IN"
+		if v.not {
+			op = "NOT IN"
+		}
+		return fmt.Sprintf("%s %s (%s)", col, op, strings.Join(placeholders, ", ")), true
+
+	case condAny:
+		col, ok := column(v.field)
+		if !ok {
+			return "", false
+		}
+		return col + " = ANY(" + b.bind(v.array) + ")", true
+
+	case condSub:
+		col, ok := column(v.field)
+		if !ok {
+			return "", false
+		}
+		op := " IN ("
+		if v.not {
+			op = " NOT IN ("
+		}
+		return col + op + b.embed(v.sub) + ")", true
+
+	case condExists:
+		return "EXISTS (" + b.embed(v.sub) + ")", true
+
+	case condBetween:
+		col, ok := column(v.field)
+		if !ok {
+			return "", false
+		}
+		op := " BETWEEN "
+		if v.not {
+			op = " NOT BETWEEN "
+		}
+		return col + op + b.bind(v.from) + " AND " + b.bind(v.to), true
+
+	case condIsNull:
+		col, ok := column(v.field)
+		if !ok {
+			return "", false
+		}
+		if v.isNull {
+			return col + " IS NULL", true
+		}
+		return col + " IS NOT NULL", true
+
+	case condGroup:
+		parts := make([]string, 0, len(v.conds))
+		for _, sub := range v.conds {
+			if sql, ok := renderCond(sub, column, b); ok {
+				parts = append(parts, sql)
 			}
-			if v.isNull {
-				conditions = append(conditions, fmt.Sprintf("%s%s%s IS NULL", pfx, f.dbName, suffix))
-			} else {
-				conditions = append(conditions, fmt.Sprintf("%s%s%s IS NOT NULL", pfx, f.dbName, suffix))
+		}
+		switch len(parts) {
+		case 0:
+			// An empty OR matches nothing and an empty AND matches
+			// everything; dropping an empty OR would widen the query.
+			if v.op == "OR" {
+				return "FALSE", true
 			}
+			return "TRUE", true
+		case 1:
+			return parts[0], true
+		}
+		return "(" + strings.Join(parts, " "+v.op+" ") + ")", true
+
+	case condNot:
+		sql, ok := renderCond(v.cond, column, b)
+		if !ok {
+			return "", false
 		}
+		return "NOT (" + sql + ")", true
 	}
 
-	return conditions, values
+	return "", false
 }
@@ -64,6 +64,12 @@ func TestBuildConditions_Comparison(t *testing.T) {
 		{"lt", Lt("age", 65), "age < $1"},
 		{"lte", Lte("age", 65), "age <= $1"},
 		{"ne", Ne("age", 0), "age != $1"},
+		{"not like", NotLike("name", "%x%"), "name NOT LIKE $1"},
+		{"ilike", ILike("name", "%x%"), "name ILIKE $1"},
+		{"regex", Regex("name", "^J"), "name ~ $1"},
+		{"iregex", IRegex("name", "^j"), "name ~* $1"},
+		{"is distinct from", IsDistinctFrom("age", nil), "age IS DISTINCT FROM $1"},
+		{"eq any", EqAny("id", []int{1, 2, 3}), "id = ANY($1)"},
 	}
 
 	for _, tt := range tests {
@@ -143,6 +149,66 @@ func TestBuildConditions_In(t *testing.T) {
 	})
 }
 
+func TestBuildConditions_InEmpty(t *testing.T) {
+	m := newCondTestModel()
+
+	t.Run("in", func(t *testing.T) {
+		conds, vals := m.BuildConditions(In("id"), Eq("name", "x"))
+		if len(conds) != 2 || conds[0] != "FALSE" || conds[1] != "name=$1" {
+			t.Errorf("got %v", conds)
+		}
+		if len(vals) != 1 {
+			t.Errorf("expected 1 val, got %d", len(vals))
+		}
+	})
+
+	t.Run("not in", func(t *testing.T) {
+		conds, vals := m.BuildConditions(NotIn("id"))
+		if len(conds) != 1 || conds[0] != "TRUE" {
+			t.Errorf("got %v", conds)
+		}
+		if len(vals) != 0 {
+			t.Errorf("expected 0 vals, got %d", len(vals))
+		}
+	})
+
+	t.Run("unknown field still skipped", func(t *testing.T) {
+		conds, _ := m.BuildConditions(In("nope"))
+		if len(conds) != 0 {
+			t.Errorf("got %v", conds)
+		}
+	})
+}
+
+func TestBuildConditions_NotIn(t *testing.T) {
+	m := newCondTestModel()
+	conds, vals := m.BuildConditions(NotIn("age", 1, 2))
+	if len(conds) != 1 || conds[0] != "age NOT IN ($1, $2)" {
+		t.Errorf("got %v", conds)
+	}
+	if len(vals) != 2 {
+		t.Errorf("expected 2 vals, got %d", len(vals))
+	}
+}
+
+func TestBuildConditions_Between(t *testing.T) {
+	m := newCondTestModel()
+
+	conds, vals := m.BuildConditions(Eq("name", "x"), Between("age", 18, 65), NotBetween("score", 1.5, 2.5))
+	want := []string{"name=$1", "age BETWEEN $2 AND $3", "score NOT BETWEEN $4 AND $5"}
+	if len(conds) != len(want) {
+		t.Fatalf("got %v", conds)
+	}
+	for i := range want {
+		if conds[i] != want[i] {
+			t.Errorf("cond %d: got %q, want %q", i, conds[i], want[i])
+		}
+	}
+	if len(vals) != 5 || vals[1] != 18 || vals[2] != 65 {
+		t.Errorf("unexpected vals: %v", vals)
+	}
+}
+
 func TestBuildConditions_Prefix(t *testing.T) {
 	m := newCondTestModel()
 
@@ -282,6 +348,83 @@ func TestBuildConditions_Empty(t *testing.T) {
 	}
 }
 
+func TestBuildConditions_Groups(t *testing.T) {
+	m := newCondTestModel()
+
+	t.Run("or", func(t *testing.T) {
+		conds, vals := m.BuildConditions(Or(Eq("age", 1), Eq("name", "x")))
+		if len(conds) != 1 || conds[0] != "(age=$1 OR name=$2)" {
+			t.Errorf("got %v", conds)
+		}
+		if len(vals) != 2 || vals[0] != 1 || vals[1] != "x" {
+			t.Errorf("unexpected vals: %v", vals)
+		}
+	})
+
+	t.Run("or and not combined", func(t *testing.T) {
+		conds, vals := m.BuildConditions(
+			Or(Eq("age", 1), Eq("score", 2.0)),
+			Not(IsNull("name", true)),
+			Gt("count", uint(3)),
+		)
+		want := []string{"(age=$1 OR score=$2)", "NOT (name IS NULL)", "count > $3"}
+		if len(conds) != 3 || conds[0] != want[0] || conds[1] != want[1] || conds[2] != want[2] {
+			t.Errorf("got %v, want %v", conds, want)
+		}
+		if len(vals) != 3 {
+			t.Errorf("expected 3 vals, got %d", len(vals))
+		}
+	})
+
+	t.Run("nested and inside or", func(t *testing.T) {
+		conds, _ := m.BuildConditions(Or(
+			And(Eq("age", 1), In("name", "a", "b")),
+			Lte("score", 5),
+		))
+		if len(conds) != 1 || conds[0] != "((age=$1 AND name IN ($2, $3)) OR score <= $4)" {
+			t.Errorf("got %v", conds)
+		}
+	})
+
+	t.Run("not of group", func(t *testing.T) {
+		conds, _ := m.BuildConditions(Not(Or(Eq("age", 1), Eq("age", 2))))
+		if len(conds) != 1 || conds[0] != "NOT ((age=$1 OR age=$2))" {
+			t.Errorf("got %v", conds)
+		}
+	})
+
+	t.Run("prefix applies inside groups", func(t *testing.T) {
+		conds, _ := m.BuildConditions(Or(Eq("age", 1), Eq("t.name", "x")), Prefix("u."))
+		if len(conds) != 1 || conds[0] != "(u.age=$1 OR t.name=$2)" {
+			t.Errorf("got %v", conds)
+		}
+	})
+
+	t.Run("single member group renders without parentheses", func(t *testing.T) {
+		conds, _ := m.BuildConditions(Or(Eq("age", 1), Eq("nonexistent", 2)))
+		if len(conds) != 1 || conds[0] != "age=$1" {
+			t.Errorf("got %v", conds)
+		}
+	})
+
+	t.Run("empty group renders constant", func(t *testing.T) {
+		conds, vals := m.BuildConditions(And(), Or(), Not(Or()), Or(Eq("nonexistent", 1)))
+		if len(conds) != 4 || conds[0] != "TRUE" || conds[1] != "FALSE" || conds[2] != "NOT (FALSE)" || conds[3] != "FALSE" || len(vals) != 0 {
+			t.Errorf("got conds=%v vals=%v", conds, vals)
+		}
+	})
+
+	t.Run("empty or in select matches no rows", func(t *testing.T) {
+		sql, _, err := m.Select(Fields("id"), WhereConds(Or()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql != "SELECT id FROM cond_test_struct WHERE FALSE" {
+			t.Errorf("got %q", sql)
+		}
+	})
+}
+
 // assertCond checks a single condition + single value result.
 func assertCond(t *testing.T, conds []string, vals []any, wantCond string, wantVal any) {
 	t.Helper()
@@ -295,3 +438,73 @@ func assertCond(t *testing.T, conds []string, vals []any, wantCond string, wantV
 		t.Errorf("vals = %v, want [%v]", vals, wantVal)
 	}
 }
+
+func TestWhereConds(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&ModelTestStruct{Id: 1, Name: "Alice", Email: "a@test.com", Age: 30})
+
+	t.Run("select", func(t *testing.T) {
+		sql, args, err := m.Select(Fields("id"), WhereConds(Eq("Name", "Alice"), Or(Lt("age", 18), Gt("age", 65))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE name=$1 AND (age < $2 OR age > $3)"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 3 || args[0] != "Alice" || args[1] != 18 || args[2] != 65 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("update binds continue after set", func(t *testing.T) {
+		sql, args, err := m.Update(Exclude("id"), WhereConds(Eq("Id", 1), Gt("Age", 18)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "UPDATE model_test_struct SET name=$1, email=$2, age=$3 WHERE id=$4 AND age > $5"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 5 || args[3] != 1 || args[4] != 18 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("combined with where", func(t *testing.T) {
+		sql, args, err := m.Delete(Where("email = ? OR email IS NULL", "x"), WhereConds(In("id", 1, 2)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "DELETE FROM model_test_struct WHERE (email = $1 OR email IS NULL) AND id IN ($2, $3)"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 3 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("repeated options accumulate", func(t *testing.T) {
+		sql, _, err := m.Delete(WhereConds(Eq("id", 1)), WhereConds(Eq("unknown", 2), IsNull("email", true)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "DELETE FROM model_test_struct WHERE id=$1 AND email IS NULL"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("soft delete filter appended", func(t *testing.T) {
+		ms, _ := n.M(&SoftUser{})
+		sql, _, err := ms.Select(Fields("id"), WhereConds(Eq("name", "x")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM soft_user WHERE name=$1 AND deleted_at IS NULL"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+}
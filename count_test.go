@@ -0,0 +1,117 @@
+package norm
+
+import "testing"
+
+func TestCount(t *testing.T) {
+	m := newTestModel()
+
+	t.Run("plain", func(t *testing.T) {
+		sql, args, err := m.Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql != "SELECT count(*) FROM model_test_struct" {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 0 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("same where as select, ignores paging", func(t *testing.T) {
+		sql, args, err := m.Count(Where("age > ?", 18), WhereConds(Eq("Name", "x")), Order("Id"), Limit(10), Offset(5))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT count(*) FROM model_test_struct WHERE (age > $1) AND name=$2"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 2 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("grouped and distinct", func(t *testing.T) {
+		tests := []struct {
+			name string
+			opts []Option
+			want string
+			args int
+		}{
+			{
+				name: "group by",
+				opts: []Option{Where("age > ?", 18), GroupBy("Name"), Order("Name"), Limit(5)},
+				want: "SELECT count(*) FROM (SELECT name FROM model_test_struct WHERE age > $1 GROUP BY name) sub",
+				args: 1,
+			},
+			{
+				name: "having",
+				opts: []Option{Where("age > ?", 18), GroupBy("Name"), Having("count(*) > ?", 2)},
+				want: "SELECT count(*) FROM (SELECT name FROM model_test_struct WHERE age > $1 GROUP BY name HAVING count(*) > $2) sub",
+				args: 2,
+			},
+			{
+				name: "distinct",
+				opts: []Option{Fields("Name"), Distinct()},
+				want: "SELECT count(*) FROM (SELECT DISTINCT name FROM model_test_struct) sub",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sql, args, err := m.Count(tt.opts...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if sql != tt.want {
+					t.Errorf("got %q\nwant %q", sql, tt.want)
+				}
+				if len(args) != tt.args {
+					t.Errorf("unexpected args: %v", args)
+				}
+			})
+		}
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		n := NewNorm(nil)
+		ms, _ := n.M(&SoftUser{})
+		sql, _, err := ms.Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql != "SELECT count(*) FROM soft_user WHERE deleted_at IS NULL" {
+			t.Errorf("got %q", sql)
+		}
+	})
+}
+
+func TestWithTotal(t *testing.T) {
+	var total int64
+	withTotal := WithTotal(&total)
+
+	user := ModelTestStruct{}
+	n := NewNorm(nil)
+	m, _ := n.M(&user)
+
+	sql, args, err := m.Select(Fields("id,name"), Where("age > ?", 18), Limit(20), withTotal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT id, name, count(*) OVER() FROM model_test_struct WHERE age > $1 LIMIT 20"
+	if sql != want {
+		t.Errorf("got %q", sql)
+	}
+	if len(args) != 1 {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	var extra int
+	ptrs := m.Pointers(Fields("id,name"), withTotal, AddTargets(&extra))
+	if len(ptrs) != 4 {
+		t.Fatalf("expected 4 pointers, got %d", len(ptrs))
+	}
+	if ptrs[0] != &user.Id || ptrs[2] != &total || ptrs[3] != &extra {
+		t.Errorf("unexpected pointers: %v", ptrs)
+	}
+}
@@ -0,0 +1,112 @@
+package norm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cte is a common table expression added with [With] or [WithRecursive].
+type cte struct {
+	name      string
+	query     Query
+	recursive bool
+}
+
+func (c *cte) Type() OptionType { return CTEOption }
+func (c *cte) Value() any       { return c.query }
+
+// NewQuery wraps the results of a builder as a [Query] for [With],
+// [InSub], [Exists] or [Model.InsertSelect], so that the builder error is
+// reported by the outer statement.
+//
+//	moved := norm.NewQuery(mTask.Delete(norm.Where("done_at < ?", cutoff), norm.Returning("*")))
+//	sql, args, _ := mArchive.InsertSelect(norm.Query{SQL: "SELECT * FROM moved"}, norm.With("moved", moved))
+func NewQuery(sql string, args []any, err error) Query {
+	return Query{SQL: sql, Args: args, err: err}
+}
+
+// With creates an option that prefixes [Model.Select], [Model.Count],
+// [Model.Insert], [Model.Update] or [Model.Delete] with a common table
+// expression: WITH name AS (query). Repeat the option for several CTEs;
+// they are rendered in order. name may include a column list, "t(a, b)".
+// The binds of q are renumbered to come first in the statement. q can be
+// a data-modifying statement, e.g. a Delete with [Returning].
+//
+//	moved := norm.NewQuery(mTask.Delete(norm.Where("done_at < ?", cutoff), norm.Returning("user_id")))
+//	sql, args, _ := mUser.Update(
+//	    norm.Fields("archived"),
+//	    norm.With("moved", moved),
+//	    norm.Where("id IN (SELECT user_id FROM moved)"),
+//	)
+//	// "WITH moved AS (DELETE FROM tasks WHERE done_at < $1 RETURNING user_id)
+//	//  UPDATE users SET archived=$2 WHERE id IN (SELECT user_id FROM moved)"
+func With(name string, q Query) Option {
+	return &cte{name: strings.TrimSpace(name), query: q}
+}
+
+// WithRecursive is like [With] but renders WITH RECURSIVE, so that q can
+// refer to name. The recursive query is usually written by hand.
+//
+//	tree := norm.Query{
+//	    SQL:  "SELECT id, parent_id FROM categories WHERE id = $1 UNION ALL SELECT c.id, c.parent_id FROM categories c JOIN tree ON c.parent_id = tree.id",
+//	    Args: []any{rootID},
+//	}
+//	sql, args, _ := m.Select(norm.WithRecursive("tree(id, parent_id)", tree), norm.Where("id IN (SELECT id FROM tree)"))
+func WithRecursive(name string, q Query) Option {
+	return &cte{name: strings.TrimSpace(name), query: q, recursive: true}
+}
+
+// withSQL renders the WITH clause with a trailing space, or "" if there
+// are no CTEs. It must be rendered before any other part of the statement
+// takes binds from b.
+func (c *Config) withSQL(b *binder, ctes []*cte) (string, error) {
+	if len(ctes) == 0 {
+		return "", nil
+	}
+
+	recursive := false
+	parts := make([]string, len(ctes))
+	for i, e := range ctes {
+		name, ok := c.cteName(e.name)
+		if !ok {
+			return "", fmt.Errorf("With: invalid name %q", e.name)
+		}
+		if e.query.err != nil {
+			return "", fmt.Errorf("With %s: %w", e.name, e.query.err)
+		}
+		recursive = recursive || e.recursive
+		parts[i] = name + " AS (" + b.embed(e.query) + ")"
+	}
+
+	if recursive {
+		return "WITH RECURSIVE " + strings.Join(parts, ", ") + " ", nil
+	}
+	return "WITH " + strings.Join(parts, ", ") + " ", nil
+}
+
+// cteName renders a CTE name, optionally followed by a parenthesized
+// column list, with the identifiers quoted as needed. Returns false if
+// name is not of that form.
+func (c *Config) cteName(name string) (string, bool) {
+	table, cols, ok := strings.Cut(name, "(")
+	table = strings.TrimSpace(table)
+	if !isValidIdentifier(table) {
+		return "", false
+	}
+	if !ok {
+		return c.ident(table), true
+	}
+	cols, ok = strings.CutSuffix(cols, ")")
+	if !ok {
+		return "", false
+	}
+	parts := strings.Split(cols, ",")
+	for i, col := range parts {
+		col = strings.TrimSpace(col)
+		if !isValidIdentifier(col) {
+			return "", false
+		}
+		parts[i] = c.ident(col)
+	}
+	return c.ident(table) + "(" + strings.Join(parts, ", ") + ")", true
+}
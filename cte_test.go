@@ -0,0 +1,161 @@
+package norm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	mUser, mOrder, _ := setupJoinModels(t)
+
+	t.Run("select", func(t *testing.T) {
+		big := mOrder.SubSelect(Fields("user_id"), Where("total > ?", 1000))
+		sql, args, err := mUser.Select(Fields("id"), With("big", big), Where("name = ? AND id IN (SELECT user_id FROM big)", "Alice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "WITH big AS (SELECT user_id FROM join_order WHERE total > $1) SELECT id FROM join_user WHERE name = $2 AND id IN (SELECT user_id FROM big)"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 2 || args[0] != 1000 || args[1] != "Alice" {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("data-modifying update", func(t *testing.T) {
+		moved := NewQuery(mOrder.Delete(Where("total < ?", 0), Returning("user_id")))
+		sql, args, err := mUser.Update(Fields("name"), With("moved", moved), Where("id IN (SELECT user_id FROM moved)"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "WITH moved AS (DELETE FROM join_order WHERE total < $1 RETURNING user_id) UPDATE join_user SET name=$2 WHERE id IN (SELECT user_id FROM moved)"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 2 || args[0] != 0 || args[1] != "Alice" {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("several ctes in delete", func(t *testing.T) {
+		a := Query{SQL: "SELECT $1::int AS id", Args: []any{1}}
+		b := Query{SQL: "SELECT $1::int AS id", Args: []any{2}}
+		sql, args, err := mUser.Delete(With("a", a), With("b(id)", b), Where("id IN (SELECT id FROM a UNION SELECT id FROM b) AND name = ?", "x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "WITH a AS (SELECT $1::int AS id), b(id) AS (SELECT $2::int AS id) DELETE FROM join_user WHERE id IN (SELECT id FROM a UNION SELECT id FROM b) AND name = $3"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 3 || args[0] != 1 || args[1] != 2 || args[2] != "x" {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("recursive in join", func(t *testing.T) {
+		tree := Query{
+			SQL:  "SELECT id FROM join_user WHERE id = $1 UNION ALL SELECT u.id FROM join_user u JOIN tree ON u.id = tree.id + 1",
+			Args: []any{1},
+		}
+		sql, args, err := NewJoin(mUser).
+			With("big", mOrder.SubSelect(Fields("id"), Where("total > ?", 10))).
+			WithRecursive("tree(id)", tree).
+			Inner(mOrder, "join_order.user_id = join_user.id").
+			Where("join_user.id IN (SELECT id FROM tree) AND join_order.id IN (SELECT id FROM big) AND join_user.name <> ?", "").
+			Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "WITH RECURSIVE big AS (SELECT id FROM join_order WHERE total > $1), tree(id) AS (SELECT id FROM join_user WHERE id = $2 UNION ALL SELECT u.id FROM join_user u JOIN tree ON u.id = tree.id + 1) SELECT join_user.id, join_user.name, join_user.email, join_order.id, join_order.user_id, join_order.total FROM join_user INNER JOIN join_order ON join_order.user_id = join_user.id WHERE join_user.id IN (SELECT id FROM tree) AND join_order.id IN (SELECT id FROM big) AND join_user.name <> $3"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 3 || args[0] != 10 || args[1] != 1 || args[2] != "" {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		q := Query{SQL: "SELECT 1"}
+		for _, name := range []string{"", "a b", "t(a", "t(a,)", "x; drop"} {
+			if _, _, err := mUser.Select(With(name, q)); err == nil {
+				t.Errorf("expected error for name %q", name)
+			}
+		}
+
+		n := NewNorm(&Config{Strict: true})
+		m, _ := n.M(&JoinOrder{})
+		_, _, err := mUser.Select(With("bad", m.SubSelect(Fields("nope"))))
+		if !errors.Is(err, ErrUnknownField) {
+			t.Errorf("expected ErrUnknownField, got %v", err)
+		}
+	})
+}
+
+type ArchiveTask struct {
+	Id     int `norm:"pk"`
+	Title  string
+	DoneAt int
+}
+
+type ArchivedTask struct {
+	Id     int `norm:"pk"`
+	Title  string
+	DoneAt int
+}
+
+func TestWithInsertSelect(t *testing.T) {
+	n := NewNorm(nil)
+	mTask, _ := n.M(&ArchiveTask{})
+	mArchive, _ := n.M(&ArchivedTask{})
+
+	t.Run("move rows with delete returning", func(t *testing.T) {
+		moved := NewQuery(mTask.Delete(Where("done_at < ?", 100), Returning("*")))
+		sql, args, err := mArchive.InsertSelect(
+			Query{SQL: "SELECT id, title, done_at FROM moved"},
+			With("moved", moved),
+			Returning("id"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "WITH moved AS (DELETE FROM archive_task WHERE done_at < $1 RETURNING *)" +
+			" INSERT INTO archived_task (id, title, done_at) SELECT id, title, done_at FROM moved RETURNING id"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 1 || args[0] != 100 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("from subselect", func(t *testing.T) {
+		src := mTask.SubSelect(Fields("id,title"), Where("done_at > ?", 5))
+		sql, args, err := mArchive.InsertSelect(src, Fields("id,title"), OnConflict().DoNothing())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO archived_task (id, title) SELECT id, title FROM archive_task WHERE done_at > $1 ON CONFLICT (id) DO NOTHING"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 1 || args[0] != 5 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, _, err := mArchive.InsertSelect(mTask.SubSelect(Fields("id"))); err == nil {
+			t.Error("expected column count error")
+		}
+		bad := NewQuery(mTask.Delete(Returning("nope")))
+		if _, _, err := mArchive.InsertSelect(Query{SQL: "SELECT * FROM moved"}, With("moved", bad)); err == nil {
+			t.Error("expected CTE error")
+		}
+		if _, _, err := mArchive.InsertSelect(NewQuery("", nil, errors.New("boom"))); err == nil {
+			t.Error("expected query error")
+		}
+	})
+}
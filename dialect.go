@@ -0,0 +1,260 @@
+package norm
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect renders the parts of a statement that differ between databases.
+// Set it with [Config.Dialect]; the default is [Postgres]. [SQLite] and
+// [MySQL] are provided, other databases can be supported by implementing
+// the interface.
+//
+// Features a database lacks, see [Feature], are reported as an error
+// wrapping [ErrUnsupported] by the builders that would render them.
+type Dialect interface {
+	// Placeholder returns the placeholder for the n-th bind argument,
+	// starting at 1. Dialects returning the same placeholder for every n
+	// ("?") bind a value again each time it is referenced.
+	Placeholder(n int) string
+
+	// Quote returns name as a quoted identifier.
+	Quote(name string) string
+
+	// LimitOffset renders a LIMIT/OFFSET clause, or "" if both are 0.
+	LimitOffset(limit, offset int) string
+
+	// Now returns the SQL expression for the current time.
+	Now() string
+
+	// Excluded returns the expression for the value proposed for insertion
+	// into col, for use in the update assignments of an upsert.
+	Excluded(col string) string
+
+	// Upsert renders the conflict clause of an INSERT. target is the
+	// rendered conflict target: "(col, ...)", "ON CONSTRAINT name" or "".
+	// cols are the inserted columns and set the "col=expr" assignments of
+	// the update; set is empty to leave the existing row unchanged.
+	Upsert(target string, cols, set []string) (string, error)
+
+	// Returning renders a RETURNING clause for the given columns.
+	Returning(cols []string) (string, error)
+
+	// CompoundMember renders one SELECT of a UNION, INTERSECT or EXCEPT,
+	// either parenthesized or bare for databases that reject parentheses
+	// around the members.
+	CompoundMember(sql string) string
+
+	// Supports reports whether the database supports the optional feature.
+	Supports(f Feature) bool
+
+	// MaxBinds returns the largest number of bind arguments allowed in a
+	// single statement.
+	MaxBinds() int
+}
+
+// ErrUnsupported is reported when a statement uses a [Feature] the
+// configured [Dialect] does not support. The error message includes the
+// method and the feature.
+var ErrUnsupported = errors.New("not supported by the dialect")
+
+// Feature is an optional SQL feature, see [Dialect.Supports].
+type Feature int
+
+const (
+	FeatureReturning      Feature = iota // RETURNING clause
+	FeatureDistinctOn                    // SELECT DISTINCT ON, see [DistinctOn]
+	FeatureLock                          // FOR UPDATE and FOR SHARE row locking
+	FeatureNoKeyUpdate                   // FOR NO KEY UPDATE, see [ForNoKeyUpdate]
+	FeatureSkipLocked                    // SKIP LOCKED and NOWAIT lock modifiers
+	FeatureILike                         // ILIKE operator, see [ILike]
+	FeatureRegex                         // ~ and ~* operators, see [Regex] and [IRegex]
+	FeatureArrays                        // array bind values, see [EqAny]
+	FeatureIsDistinctFrom                // IS DISTINCT FROM, see [IsDistinctFrom]
+)
+
+var featureNames = [...]string{
+	FeatureReturning:      "RETURNING",
+	FeatureDistinctOn:     "DISTINCT ON",
+	FeatureLock:           "row locking",
+	FeatureNoKeyUpdate:    "FOR NO KEY UPDATE",
+	FeatureSkipLocked:     "SKIP LOCKED and NOWAIT",
+	FeatureILike:          "ILIKE",
+	FeatureRegex:          "regular expression operators",
+	FeatureArrays:         "= ANY(array)",
+	FeatureIsDistinctFrom: "IS DISTINCT FROM",
+}
+
+// String returns the SQL name of the feature.
+func (f Feature) String() string {
+	if f >= 0 && int(f) < len(featureNames) {
+		return featureNames[f]
+	}
+	return "Feature(" + strconv.Itoa(int(f)) + ")"
+}
+
+// unsupported returns an error wrapping [ErrUnsupported] if d does not
+// support f, or nil.
+func unsupported(method string, d Dialect, f Feature) error {
+	if d.Supports(f) {
+		return nil
+	}
+	return fmt.Errorf("%s: %s %w", method, f, ErrUnsupported)
+}
+
+var (
+	// Postgres is the PostgreSQL dialect: $1 placeholders, double-quoted
+	// identifiers and ON CONFLICT upserts.
+	Postgres Dialect = postgres{}
+
+	// SQLite is the SQLite dialect: ? placeholders, double-quoted
+	// identifiers and ON CONFLICT upserts (SQLite 3.35 or newer for
+	// RETURNING, 3.39 for IS DISTINCT FROM). Row locking, DISTINCT ON,
+	// ILIKE, regular expressions and arrays are not supported.
+	SQLite Dialect = sqlite{}
+
+	// MySQL is the MySQL dialect: ? placeholders, backtick-quoted
+	// identifiers and ON DUPLICATE KEY UPDATE upserts (MySQL 8.0 for
+	// SKIP LOCKED and NOWAIT). RETURNING, DISTINCT ON, FOR NO KEY UPDATE,
+	// ILIKE, IS DISTINCT FROM, the ~ operators and arrays are not supported.
+	MySQL Dialect = mysql{}
+)
+
+type postgres struct{}
+
+func (postgres) Placeholder(n int) string { return "$" + strconv.Itoa(n) }
+func (postgres) Quote(name string) string { return quoteWith(name, '"') }
+func (postgres) Now() string              { return "now()" }
+func (postgres) Excluded(col string) string {
+	return "EXCLUDED." + col
+}
+
+func (postgres) LimitOffset(limit, offset int) string {
+	var parts []string
+	if limit > 0 {
+		parts = append(parts, fmt.Sprintf("LIMIT %d", limit))
+	}
+	if offset > 0 {
+		parts = append(parts, fmt.Sprintf("OFFSET %d", offset))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (postgres) Upsert(target string, cols, set []string) (string, error) {
+	sql := " ON CONFLICT"
+	if target != "" {
+		sql += " " + target
+	}
+	if len(set) == 0 {
+		return sql + " DO NOTHING", nil
+	}
+	return sql + " DO UPDATE SET " + strings.Join(set, ", "), nil
+}
+
+func (postgres) Returning(cols []string) (string, error) {
+	return " RETURNING " + strings.Join(cols, ", "), nil
+}
+
+func (postgres) CompoundMember(sql string) string { return "(" + sql + ")" }
+func (postgres) Supports(Feature) bool            { return true }
+func (postgres) MaxBinds() int                    { return 65535 }
+
+// sqlite shares the upsert and RETURNING syntax of PostgreSQL.
+type sqlite struct{ postgres }
+
+func (sqlite) Placeholder(int) string { return "?" }
+func (sqlite) Now() string            { return "CURRENT_TIMESTAMP" }
+
+// SQLite does not accept parenthesized compound members.
+func (sqlite) CompoundMember(sql string) string { return sql }
+
+func (sqlite) Supports(f Feature) bool {
+	switch f {
+	case FeatureReturning, FeatureIsDistinctFrom:
+		return true
+	}
+	return false
+}
+
+// SQLITE_MAX_VARIABLE_NUMBER defaults to 32766 since SQLite 3.32.
+func (sqlite) MaxBinds() int { return 32766 }
+
+// OFFSET requires a LIMIT in SQLite; -1 means no limit.
+func (d sqlite) LimitOffset(limit, offset int) string {
+	if limit <= 0 && offset > 0 {
+		return fmt.Sprintf("LIMIT -1 OFFSET %d", offset)
+	}
+	return d.postgres.LimitOffset(limit, offset)
+}
+
+func (sqlite) Upsert(target string, cols, set []string) (string, error) {
+	if strings.HasPrefix(target, "ON CONSTRAINT") {
+		return "", errors.New("SQLite does not support ON CONFLICT ON CONSTRAINT")
+	}
+	return postgres{}.Upsert(target, cols, set)
+}
+
+type mysql struct{}
+
+func (mysql) Placeholder(int) string   { return "?" }
+func (mysql) Quote(name string) string { return quoteWith(name, '`') }
+func (mysql) Now() string              { return "now()" }
+func (mysql) Excluded(col string) string {
+	return "VALUES(" + col + ")"
+}
+
+// OFFSET requires a LIMIT in MySQL; the documented way to skip rows
+// without a limit is the largest unsigned 64-bit value.
+func (mysql) LimitOffset(limit, offset int) string {
+	if limit <= 0 && offset > 0 {
+		return fmt.Sprintf("LIMIT 18446744073709551615 OFFSET %d", offset)
+	}
+	return postgres{}.LimitOffset(limit, offset)
+}
+
+// Upsert ignores the conflict target: ON DUPLICATE KEY UPDATE applies to
+// any unique key. Leaving the row unchanged is a no-op assignment.
+func (mysql) Upsert(_ string, cols, set []string) (string, error) {
+	if len(set) == 0 {
+		if len(cols) == 0 {
+			return "", errors.New("MySQL upsert needs at least one column")
+		}
+		set = []string{cols[0] + "=" + cols[0]}
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(set, ", "), nil
+}
+
+func (mysql) Returning([]string) (string, error) {
+	return "", errors.New("MySQL does not support RETURNING")
+}
+
+func (mysql) CompoundMember(sql string) string { return "(" + sql + ")" }
+func (mysql) MaxBinds() int                    { return 65535 }
+
+func (mysql) Supports(f Feature) bool {
+	switch f {
+	case FeatureLock, FeatureSkipLocked:
+		return true
+	}
+	return false
+}
+
+// quoteWith quotes name with q, doubling any q inside it.
+func quoteWith(name string, q byte) string {
+	s := string(q)
+	return s + strings.ReplaceAll(name, s, s+s) + s
+}
+
+// bareMembers reports whether d renders compound members without
+// parentheses, see [Dialect.CompoundMember].
+func bareMembers(d Dialect) bool {
+	return d.CompoundMember("x") == "x"
+}
+
+// positional reports whether d uses the same placeholder for every bind
+// argument, like "?".
+func positional(d Dialect) bool {
+	return d.Placeholder(1) == d.Placeholder(2)
+}
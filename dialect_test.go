@@ -0,0 +1,361 @@
+package norm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newDialectModel(t *testing.T, d Dialect) *Model {
+	t.Helper()
+	n := NewNorm(&Config{Dialect: d})
+	m, err := n.M(&ModelTestStruct{Id: 1, Name: "John", Email: "j@x", Age: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestDialectSelect(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{Postgres, "SELECT id FROM model_test_struct WHERE (name = $1 AND age IN ($2, $3)) AND email=$4 LIMIT 10 OFFSET 20"},
+		{SQLite, "SELECT id FROM model_test_struct WHERE (name = ? AND age IN (?, ?)) AND email=? LIMIT 10 OFFSET 20"},
+		{MySQL, "SELECT id FROM model_test_struct WHERE (name = ? AND age IN (?, ?)) AND email=? LIMIT 10 OFFSET 20"},
+	}
+
+	for _, tt := range tests {
+		m := newDialectModel(t, tt.dialect)
+		sql, args, err := m.Select(
+			Fields("id"),
+			Where("name = ? AND age IN (?)", "John", []int{1, 2}),
+			WhereConds(Eq("Email", "j@x")),
+			Limit(10), Offset(20),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql != tt.want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, tt.want)
+		}
+		if len(args) != 4 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	}
+}
+
+func TestDialectLimitOffset(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{Postgres, "OFFSET 5"},
+		{SQLite, "LIMIT -1 OFFSET 5"},
+		{MySQL, "LIMIT 18446744073709551615 OFFSET 5"},
+	}
+	for _, tt := range tests {
+		if got := newDialectModel(t, tt.dialect).LimitOffset(0, 5); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestDialectRepeatedBinds(t *testing.T) {
+	m := newDialectModel(t, SQLite)
+
+	t.Run("named", func(t *testing.T) {
+		sql, args, err := m.Select(Fields("id"), WhereNamed("name = :q OR email = :q OR age > :age", map[string]any{"q": "x", "age": 5}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql != "SELECT id FROM model_test_struct WHERE name = ? OR email = ? OR age > ?" {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 3 || args[0] != "x" || args[1] != "x" || args[2] != 5 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("keyset mixed directions", func(t *testing.T) {
+		sql, args, err := m.Select(Fields("id"), Order("Name ASC, Id DESC"), After(Cursor{Values: []any{"a", 7}}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE (name > ? OR (name = ? AND id < ?)) ORDER BY name ASC, id DESC"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 3 || args[0] != "a" || args[1] != "a" || args[2] != 7 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("embedded numbered query", func(t *testing.T) {
+		pg := newDialectModel(t, Postgres)
+		sub := pg.SubSelect(Fields("id"), WhereNamed("name = :q OR email = :q", map[string]any{"q": "x"}))
+		sql, args, err := m.Select(Fields("id"), Where("age > ?", 0), WhereConds(InSub("Id", sub)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE (age > ?) AND id IN (SELECT id FROM model_test_struct WHERE name = ? OR email = ?)"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 3 || args[1] != "x" || args[2] != "x" {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("union", func(t *testing.T) {
+		pg := newDialectModel(t, Postgres)
+		sql, args, err := Union(
+			m.SubSelect(Fields("id"), Where("age > ?", 1)),
+			m.SubSelect(Fields("id"), Where("age < ?", 2)),
+		).Offset(3).Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE age > ? UNION SELECT id FROM model_test_struct WHERE age < ? LIMIT -1 OFFSET 3"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 2 {
+			t.Errorf("unexpected args: %v", args)
+		}
+
+		// nested compounds keep their precedence without parentheses
+		nested := Except(m.SubSelect(Fields("id")), m.SubSelect(Fields("id"), Where("age < ?", 2)))
+		sql, _, err = Union(m.SubSelect(Fields("id"), Where("age > ?", 1)), nested.SubSelect()).Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = "SELECT id FROM model_test_struct WHERE age > ? UNION SELECT * FROM (SELECT id FROM model_test_struct EXCEPT SELECT id FROM model_test_struct WHERE age < ?)"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+
+		// members of different dialects cannot be combined
+		if _, _, err := Union(m.SubSelect(Fields("id")), pg.SubSelect(Fields("id"))).Select(); err == nil {
+			t.Error("expected dialect mismatch error")
+		}
+
+		// a positional subquery inside a numbered statement
+		sql, _, err = pg.Select(Fields("id"), Where("age > ?", 0), WhereConds(Exists(m.SubSelect(Fields("id"), Where("age < ?", 2)))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(sql, "EXISTS (SELECT id FROM model_test_struct WHERE age < $2)") {
+			t.Errorf("got %q", sql)
+		}
+	})
+}
+
+func TestDialectWriteHelpers(t *testing.T) {
+	m := newDialectModel(t, MySQL)
+
+	if got := m.Binds(Exclude("id")); got != "?, ?, ?" {
+		t.Errorf("Binds: got %q", got)
+	}
+	if got, next := m.UpdateFields(Exclude("id")); got != "name=?, email=?, age=?" || next != 4 {
+		t.Errorf("UpdateFields: got %q, %d", got, next)
+	}
+}
+
+func TestDialectUpsert(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		opt     Option
+		want    string
+	}{
+		{"postgres update", Postgres, OnConflict("Email").DoUpdate(), "INSERT INTO model_test_struct (name, email, age) VALUES ($1, $2, $3) ON CONFLICT (email) DO UPDATE SET name=EXCLUDED.name, age=EXCLUDED.age"},
+		{"sqlite update", SQLite, OnConflict("Email").DoUpdate(), "INSERT INTO model_test_struct (name, email, age) VALUES (?, ?, ?) ON CONFLICT (email) DO UPDATE SET name=EXCLUDED.name, age=EXCLUDED.age"},
+		{"mysql update", MySQL, OnConflict("Email").DoUpdate(), "INSERT INTO model_test_struct (name, email, age) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE name=VALUES(name), age=VALUES(age)"},
+		{"mysql nothing", MySQL, OnConflict().DoNothing(), "INSERT INTO model_test_struct (name, email, age) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE name=name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newDialectModel(t, tt.dialect)
+			sql, _, err := m.Insert(Exclude("id"), tt.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.want {
+				t.Errorf("got:\n  %q\nwant:\n  %q", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		if _, _, err := newDialectModel(t, MySQL).Insert(Returning("id")); err == nil {
+			t.Error("expected RETURNING error for MySQL")
+		}
+		if _, _, err := newDialectModel(t, SQLite).Insert(OnConstraint("c").DoNothing()); err == nil {
+			t.Error("expected ON CONSTRAINT error for SQLite")
+		}
+	})
+}
+
+func TestDialectUnsupported(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		build   func(m *Model) error
+		want    string
+	}{
+		{"sqlite lock", SQLite, func(m *Model) error {
+			_, _, err := m.Select(ForUpdate())
+			return err
+		}, "Select: row locking not supported by the dialect"},
+		{"mysql no key update", MySQL, func(m *Model) error {
+			_, _, err := m.Select(ForNoKeyUpdate())
+			return err
+		}, "Select: FOR NO KEY UPDATE not supported by the dialect"},
+		{"mysql distinct on", MySQL, func(m *Model) error {
+			_, _, err := m.Select(DistinctOn("Name"))
+			return err
+		}, "Select: DISTINCT ON not supported by the dialect"},
+		{"mysql ilike", MySQL, func(m *Model) error {
+			_, _, err := m.BuildConditionsE(ILike("Name", "%j%"))
+			return err
+		}, "BuildConditionsE: ILIKE not supported by the dialect"},
+		{"mysql nested regex", MySQL, func(m *Model) error {
+			_, _, err := m.BuildConditionsE(Or(Eq("Age", 1), Not(Regex("Name", "^j"))))
+			return err
+		}, "BuildConditionsE: regular expression operators not supported by the dialect"},
+		{"mysql is distinct from", MySQL, func(m *Model) error {
+			_, _, err := m.Select(WhereConds(IsDistinctFrom("Email", nil)))
+			return err
+		}, "WhereConds: IS DISTINCT FROM not supported by the dialect"},
+		{"sqlite eq any", SQLite, func(m *Model) error {
+			_, _, err := m.Select(WhereConds(EqAny("Id", []int{1, 2})))
+			return err
+		}, "WhereConds: = ANY(array) not supported by the dialect"},
+		{"mysql returning", MySQL, func(m *Model) error {
+			_, _, err := m.Insert(Returning("Id"))
+			return err
+		}, "Returning: RETURNING not supported by the dialect"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.build(newDialectModel(t, tt.dialect))
+			if !errors.Is(err, ErrUnsupported) || err.Error() != tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		m := newDialectModel(t, MySQL)
+		if _, _, err := m.Select(ForUpdate().SkipLocked()); err != nil {
+			t.Error(err)
+		}
+		if _, _, err := newDialectModel(t, SQLite).BuildConditionsE(IsDistinctFrom("Email", nil)); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("join", func(t *testing.T) {
+		n := NewNorm(&Config{Dialect: SQLite})
+		mUser, _ := n.M(&JoinUser{})
+		mOrder, _ := n.M(&JoinOrder{})
+		_, _, err := NewJoin(mUser).
+			Inner(mOrder, "join_order.user_id = join_user.id").
+			WhereConds(ILike("join_user.Name", "%j%")).
+			Select()
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("got %v", err)
+		}
+		_, _, err = NewJoin(mUser).
+			Inner(mOrder, "join_order.user_id = join_user.id").
+			Lock(ForShare()).
+			Select()
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("got %v", err)
+		}
+	})
+
+	t.Run("build conditions panics", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic")
+			}
+		}()
+		newDialectModel(t, MySQL).BuildConditions(EqAny("Id", []int{1}))
+	})
+}
+
+func TestDialectNow(t *testing.T) {
+	n := NewNorm(&Config{Dialect: SQLite})
+	m, _ := n.M(&SoftUser{Id: 1})
+	sql, _, err := m.Delete(Where("id = ?", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "UPDATE soft_user SET deleted_at=CURRENT_TIMESTAMP WHERE (id = ?) AND deleted_at IS NULL"
+	if sql != want {
+		t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+	}
+}
+
+func TestDialectJoin(t *testing.T) {
+	n := NewNorm(&Config{Dialect: MySQL})
+	mUser, _ := n.M(&JoinUser{})
+	mOrder, _ := n.M(&JoinOrder{})
+
+	sql, _, err := NewJoin(mUser).
+		Inner(mOrder, "join_order.user_id = join_user.id").
+		Where("join_order.total > ?", 1).
+		Offset(10).
+		Select()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(sql, "WHERE join_order.total > ? LIMIT 18446744073709551615 OFFSET 10") {
+		t.Errorf("got %q", sql)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		name    string
+		want    string
+	}{
+		{Postgres, "order", `"order"`},
+		{Postgres, `a"b`, `"a""b"`},
+		{SQLite, "User", `"User"`},
+		{MySQL, "order", "`order`"},
+		{MySQL, "a`b", "`a``b`"},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.Quote(tt.name); got != tt.want {
+			t.Errorf("Quote(%q): got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDialectBuildWhere(t *testing.T) {
+	m := newDialectModel(t, MySQL)
+
+	set, next := m.UpdateFields(Fields("name,email"))
+	where, args := m.BuildWhere(next, "id = ? AND age IN (?)", 1, []int{2, 3})
+	if set != "name=?, email=?" || where != "id = ? AND age IN (?, ?)" {
+		t.Errorf("got %q, %q", set, where)
+	}
+	if len(args) != 3 {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	co := ComposeOptions(Where("name = ?", "x"))
+	if got, next := co.Where.BuildDialect(SQLite, 3); got != "name = ?" || next != 4 {
+		t.Errorf("BuildDialect: got %q, %d", got, next)
+	}
+	if got, _ := co.Where.BuildDialect(Postgres, 3); got != "name = $3" {
+		t.Errorf("BuildDialect: got %q", got)
+	}
+}
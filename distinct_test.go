@@ -0,0 +1,117 @@
+package norm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelectDistinct(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&AggOrder{})
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			"distinct",
+			[]Option{Fields("status"), Distinct()},
+			"SELECT DISTINCT status FROM agg_order",
+		},
+		{
+			"distinct on",
+			[]Option{Fields("id,user_id"), DistinctOn("UserId"), Order("UserId, Total DESC")},
+			"SELECT DISTINCT ON (user_id) id, user_id FROM agg_order ORDER BY user_id ASC, total DESC",
+		},
+		{
+			"distinct on several fields in any order",
+			[]Option{Fields("id"), DistinctOn("user_id, Status"), Order("status DESC, user_id, id")},
+			"SELECT DISTINCT ON (user_id, status) id FROM agg_order ORDER BY status DESC, user_id ASC, id ASC",
+		},
+		{
+			"distinct on without order",
+			[]Option{Fields("id"), DistinctOn("UserId")},
+			"SELECT DISTINCT ON (user_id) id FROM agg_order",
+		},
+		{
+			"distinct on with prefix",
+			[]Option{Fields("id"), Prefix("o."), DistinctOn("UserId"), Order("UserId")},
+			"SELECT DISTINCT ON (o.user_id) o.id FROM agg_order ORDER BY user_id ASC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := m.Select(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.want {
+				t.Errorf("got:\n  %q\nwant:\n  %q", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, _, err := m.Select(DistinctOn("Nope"))
+		if !errors.Is(err, ErrUnknownField) {
+			t.Errorf("expected ErrUnknownField, got %v", err)
+		}
+	})
+
+	t.Run("order does not start with distinct on", func(t *testing.T) {
+		orders := []string{"Total DESC, UserId", "UserId, UserId, Status"}
+		for _, order := range orders {
+			if _, _, err := m.Select(DistinctOn("UserId, Status"), Order(order)); err == nil {
+				t.Errorf("expected error for order %q", order)
+			}
+		}
+	})
+}
+
+func TestJoinDistinct(t *testing.T) {
+	mUser, mOrder, _ := setupJoinModels(t)
+
+	t.Run("distinct on", func(t *testing.T) {
+		sql, _, err := NewJoin(mUser).
+			Inner(mOrder, "join_order.user_id = join_user.id").
+			DistinctOn("join_order.UserId").
+			Order("join_order.user_id, join_order.total DESC").
+			Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT DISTINCT ON (join_order.user_id) join_user.id, join_user.name, join_user.email, join_order.id, join_order.user_id, join_order.total FROM join_user INNER JOIN join_order ON join_order.user_id = join_user.id ORDER BY join_order.user_id, join_order.total DESC"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+
+	t.Run("distinct", func(t *testing.T) {
+		sql, _, err := NewJoin(mUser).Inner(mOrder, "join_order.user_id = join_user.id").Distinct().Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT DISTINCT join_user.id, join_user.name, join_user.email, join_order.id, join_order.user_id, join_order.total FROM join_user INNER JOIN join_order ON join_order.user_id = join_user.id"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := NewJoin(mUser).DistinctOn("Nope").Select()
+		if !errors.Is(err, ErrUnknownField) {
+			t.Errorf("expected ErrUnknownField, got %v", err)
+		}
+
+		_, _, err = NewJoin(mUser).
+			Inner(mOrder, "join_order.user_id = join_user.id").
+			DistinctOn("join_order.UserId").
+			Order("join_order.total DESC").
+			Select()
+		if err == nil {
+			t.Error("expected ORDER BY error")
+		}
+	})
+}
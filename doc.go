@@ -4,7 +4,8 @@
 // full SELECT/INSERT/UPDATE/DELETE queries) from struct definitions using
 // reflection. It is not an ORM — it does not execute queries or manage
 // connections. You compose the generated SQL with any PostgreSQL driver (pgx,
-// database/sql, etc.).
+// database/sql, etc.), or with SQLite and MySQL drivers by setting
+// [Config.Dialect].
 //
 // # Quick start
 //
@@ -47,14 +48,18 @@
 //
 // Fields are configured via the "norm" struct tag:
 //
-//	pk           — mark as primary key
-//	notnull      — NOT NULL constraint
-//	unique       — UNIQUE constraint
-//	default=val  — DEFAULT value
-//	dbName=name  — override column name
-//	dbType=type  — override PostgreSQL type
-//	fk=Model     — foreign key (accepts CamelCase, camelCase, snake_case)
-//	-            — skip field entirely
+//	pk             — mark as primary key
+//	notnull        — NOT NULL constraint
+//	unique         — UNIQUE constraint
+//	default=val    — DEFAULT value
+//	dbName=name    — override column name
+//	dbType=type    — override PostgreSQL type
+//	fk=Model       — foreign key (accepts CamelCase, camelCase, snake_case)
+//	softdelete     — soft-delete timestamp, see [Model.Delete] and [WithDeleted]
+//	autoCreateTime — set to the current time on insert
+//	autoUpdateTime — set to the current time on insert and update
+//	version        — optimistic locking counter, see [CheckVersion]
+//	-              — skip field entirely
 //
 // # Configuration
 //
@@ -66,6 +71,12 @@
 //	    DefaultJSON:   "json",         // default: "jsonb"
 //	    JSONMarshal:   sonic.Marshal,  // default: encoding/json
 //	    JSONUnmarshal: sonic.Unmarshal,
+//	    NowFunc:       time.Now,       // default: now() in SQL
+//	    Strict:        true,           // default: ignore unknown field names
+//	    SlicesAsArrays: true,          // default: expand slices in Where templates
+//	    Dialect:       norm.SQLite,    // default: norm.Postgres
+//	    QuoteIdentifiers: true,        // default: quote reserved and mixed-case names only
+//	    DefaultSchema: "app",          // default: unqualified table names
 //	})
 //
 // # Thread safety
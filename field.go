@@ -23,6 +23,11 @@ func (f *Field) hasTag(tag string) bool {
 	return ok
 }
 
+// isAutoTime reports whether the field is set to the current time on insert.
+func (f *Field) isAutoTime() bool {
+	return f.hasTag("autoCreateTime") || f.hasTag("autoUpdateTime")
+}
+
 // Tag returns the value of a norm tag key and whether it exists.
 //
 //	val, ok := field.Tag("default") // val="0", ok=true for `norm:"default=0"`
@@ -23,6 +23,16 @@ func has(a []string, val string) bool {
 	return false
 }
 
+// containsField reports whether ff contains f.
+func containsField(ff []*Field, f *Field) bool {
+	for _, x := range ff {
+		if x == f {
+			return true
+		}
+	}
+	return false
+}
+
 // Binds generates a bind placeholder string in "$1, $2, ..." format
 // for the given number of parameters.
 //
@@ -3,8 +3,6 @@ package norm
 import (
 	"fmt"
 	"strings"
-
-	"github.com/iancoleman/strcase"
 )
 
 type joinType string
@@ -32,12 +30,18 @@ type joinEntry struct {
 //	sql, args, _ := j.Select()
 //	err := row.Scan(j.Pointers()...)
 type Join struct {
-	base    *Model
-	joins   []joinEntry
-	where   *whereOption
-	orderBy string
-	limit   int
-	offset  int
+	base        *Model
+	ctes        []*cte
+	joins       []joinEntry
+	where       *whereOption
+	conds       []Cond
+	orderBy     string
+	distinct    bool
+	distinctOn  []string
+	limit       int
+	offset      int
+	withDeleted bool
+	lock        Lock
 }
 
 // NewJoin creates a new [Join] builder with the given base (FROM) model.
@@ -79,6 +83,9 @@ func (j *Join) Right(m *Model, on string) *Join {
 //
 //	// Given: Order has `norm:"fk=User"` on UserId field
 //	j.Auto(mOrder) // → INNER JOIN orders ON orders.user_id = users.id
+//
+// An fk tag without a schema references a table in the schema of the
+// tagged model; write `norm:"fk=billing.Invoice"` to join across schemas.
 func (j *Join) Auto(m *Model) *Join {
 	j.joins = append(j.joins, joinEntry{innerJoin, m, j.resolveFK(m)})
 	return j
@@ -91,6 +98,21 @@ func (j *Join) AutoLeft(m *Model) *Join {
 	return j
 }
 
+// With adds a common table expression, see [With].
+//
+//	j.With("big", mOrder.SubSelect(norm.Fields("id"), norm.Where("total > ?", 1000)))
+func (j *Join) With(name string, q Query) *Join {
+	j.ctes = append(j.ctes, With(name, q).(*cte))
+	return j
+}
+
+// WithRecursive adds a recursive common table expression, see
+// [WithRecursive].
+func (j *Join) WithRecursive(name string, q Query) *Join {
+	j.ctes = append(j.ctes, WithRecursive(name, q).(*cte))
+	return j
+}
+
 // Where sets the WHERE clause with "?" placeholders for positional args.
 //
 //	j.Where("users.active = ? AND orders.total > ?", true, 100)
@@ -99,6 +121,27 @@ func (j *Join) Where(where string, args ...any) *Join {
 	return j
 }
 
+// WhereNamed sets the WHERE clause with ":name" placeholders bound from
+// params, see [WhereNamed].
+//
+//	j.WhereNamed("users.active = :active AND orders.total > :min", map[string]any{"active": true, "min": 100})
+func (j *Join) WhereNamed(where string, params map[string]any) *Join {
+	j.where = namedWhere(where, params)
+	return j
+}
+
+// WhereConds adds WHERE conditions built from typed [Cond] values, joined
+// with AND and combined with [Join.Where]. Fields prefixed with a table name
+// ("orders.Total") are resolved against that model, unprefixed fields
+// against the base model. Conditions on unknown fields are skipped, or
+// make Select fail in [Config.Strict] mode.
+//
+//	j.WhereConds(norm.Eq("users.Active", true), norm.Gt("orders.Total", 100))
+func (j *Join) WhereConds(conds ...Cond) *Join {
+	j.conds = append(j.conds, conds...)
+	return j
+}
+
 // Order sets the ORDER BY clause. Use raw SQL with table.column format.
 //
 //	j.Order("users.name DESC, orders.total ASC")
@@ -107,6 +150,26 @@ func (j *Join) Order(orderBy string) *Join {
 	return j
 }
 
+// Distinct makes the query SELECT DISTINCT.
+func (j *Join) Distinct() *Join {
+	j.distinct = true
+	j.distinctOn = nil
+	return j
+}
+
+// DistinctOn makes the query SELECT DISTINCT ON (...) with the given
+// comma-separated fields, resolved like [Join.WhereConds] fields. Select
+// fails on unknown fields and when [Join.Order] does not start with the
+// DISTINCT ON fields.
+//
+//	j.DistinctOn("orders.UserId").Order("orders.user_id, orders.id DESC")
+//	// "SELECT DISTINCT ON (orders.user_id) ..."
+func (j *Join) DistinctOn(fields string) *Join {
+	j.distinct = true
+	j.distinctOn = strings.Split(fields, ",")
+	return j
+}
+
 // Limit sets the LIMIT value for the query.
 func (j *Join) Limit(limit int) *Join {
 	j.limit = limit
@@ -119,8 +182,27 @@ func (j *Join) Offset(offset int) *Join {
 	return j
 }
 
+// Lock adds a row-locking clause after LIMIT/OFFSET. Use [Lock.Of] to lock
+// only the rows of some of the joined tables.
+//
+//	j.Lock(norm.ForUpdate().Of(mOrder).SkipLocked())
+//	// "... FOR UPDATE OF orders SKIP LOCKED"
+func (j *Join) Lock(lock Lock) *Join {
+	j.lock = lock
+	return j
+}
+
+// WithDeleted disables the automatic soft-delete filters, so rows that are
+// soft-deleted in any of the joined models are included.
+func (j *Join) WithDeleted() *Join {
+	j.withDeleted = true
+	return j
+}
+
 // Select builds the full SELECT ... FROM ... JOIN ... query.
 // All column names are prefixed with their table names.
+// Soft-deleted rows are filtered out: for the base model in WHERE, for
+// joined models in their ON clause so that outer joins keep working.
 // Returns the SQL string, positional arguments, and any error.
 func (j *Join) Select() (string, []any, error) {
 	allFields := j.collectFields(j.base)
@@ -128,32 +210,171 @@ func (j *Join) Select() (string, []any, error) {
 		allFields = append(allFields, j.collectFields(je.model)...)
 	}
 
-	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(allFields, ", "), j.base.Table())
+	if err := j.lock.check(j.base.config.Dialect, ComposedOptions{Distinct: j.distinct, DistinctOn: j.distinctOn}); err != nil {
+		return "", nil, err
+	}
 
-	for _, je := range j.joins {
-		sql += fmt.Sprintf(" %s %s ON %s", je.jType, je.model.Table(), je.on)
+	distinct, err := j.distinctSQL()
+	if err != nil {
+		return "", nil, err
 	}
 
-	var args []any
+	b := j.base.newBinder()
+	sql, err := j.base.config.withSQL(b, j.ctes)
+	if err != nil {
+		return "", nil, err
+	}
+	sql += fmt.Sprintf("SELECT %s%s FROM %s", distinct, strings.Join(allFields, ", "), j.base.ident(j.base.Table()))
+
+	for _, je := range j.joins {
+		on := je.on
+		if sd := j.softDeleteWhere(je.model); sd != nil {
+			on = "(" + on + ") AND " + sd.template
+		}
+		sql += fmt.Sprintf(" %s %s ON %s", je.jType, je.model.ident(je.model.Table()), on)
+	}
 
-	if j.where != nil {
-		whereStr, _ := j.where.Build(1)
-		sql += " WHERE " + whereStr
-		args = append(args, j.where.Args...)
+	where, err := renderWhere(b, j.where, condsWhere(j.conds, j.condColumn, j.onUnknown()), j.softDeleteWhere(j.base))
+	if err != nil {
+		return "", nil, err
 	}
+	sql += where
 
 	if j.orderBy != "" {
 		sql += " ORDER BY " + j.orderBy
 	}
 
-	if j.limit > 0 {
-		sql += fmt.Sprintf(" LIMIT %d", j.limit)
+	if lo := j.base.config.Dialect.LimitOffset(j.limit, j.offset); lo != "" {
+		sql += " " + lo
+	}
+
+	sql += j.lock.sql()
+
+	return sql, b.args, nil
+}
+
+// distinctSQL renders the DISTINCT or DISTINCT ON (...) select modifier
+// with a trailing space, or "" if neither is set. ORDER BY columns that
+// name model fields are resolved before they are compared.
+func (j *Join) distinctSQL() (string, error) {
+	if !j.distinct {
+		return "", nil
+	}
+	if len(j.distinctOn) == 0 {
+		return "DISTINCT ", nil
+	}
+	if err := unsupported("Select", j.base.config.Dialect, FeatureDistinctOn); err != nil {
+		return "", err
+	}
+
+	on := make([]string, len(j.distinctOn))
+	for i, field := range j.distinctOn {
+		col, ok := j.condColumn(strings.TrimSpace(field))
+		if !ok {
+			return "", fmt.Errorf("Select: %w %q in join of %q", ErrUnknownField, strings.TrimSpace(field), j.base.Table())
+		}
+		on[i] = col
+	}
+
+	var order []string
+	for _, part := range strings.Split(j.orderBy, ",") {
+		tokens := strings.Fields(part)
+		if len(tokens) == 0 {
+			continue
+		}
+		col, ok := j.condColumn(tokens[0])
+		if !ok {
+			col = tokens[0]
+		}
+		order = append(order, col)
+	}
+	if err := checkDistinctOn(on, order); err != nil {
+		return "", err
+	}
+	return "DISTINCT ON (" + strings.Join(on, ", ") + ") ", nil
+}
+
+// SubSelect builds the query like [Join.Select] for use inside another
+// statement with [InSub], [Exists], [With] or [Union].
+func (j *Join) SubSelect() Query {
+	q := NewQuery(j.Select())
+	q.dialect = j.base.config.Dialect
+	q.cols = len(j.collectFields(j.base))
+	for _, je := range j.joins {
+		q.cols += len(j.collectFields(je.model))
+	}
+	return q
+}
+
+// softDeleteWhere returns the soft-delete filter for a joined model, or nil
+// if it has none or [Join.WithDeleted] was called.
+func (j *Join) softDeleteWhere(m *Model) *whereOption {
+	if j.withDeleted {
+		return nil
+	}
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	return m.softDeleteWhere(m.ident(m.table)+".", excludeDeleted)
+}
+
+// condColumn resolves a condition field against the joined models: a
+// "table." prefix selects the model, no prefix means the base model.
+// Columns are always rendered with the table name.
+func (j *Join) condColumn(field string) (string, bool) {
+	prefix, name, suffix := parseFieldParts(field)
+
+	m := j.base
+	if prefix != "" {
+		m = j.modelByTable(strings.TrimSuffix(prefix, "."))
+		if m == nil {
+			return "", false
+		}
+	}
+
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	f, ok := m.fieldByAnyName[name]
+	if !ok {
+		return "", false
 	}
-	if j.offset > 0 {
-		sql += fmt.Sprintf(" OFFSET %d", j.offset)
+	return m.ident(m.table) + "." + m.ident(f.dbName) + suffix, true
+}
+
+// onUnknown returns the handler for condition fields that cannot be
+// resolved: an error in [Config.Strict] mode of the base model, nil
+// (skip the condition) otherwise.
+func (j *Join) onUnknown() func(field string) error {
+	if !j.base.config.Strict {
+		return nil
+	}
+	return func(field string) error {
+		return fmt.Errorf("Select: %w %q in join of %q", ErrUnknownField, field, j.base.Table())
 	}
+}
 
-	return sql, args, nil
+// modelByTable returns the base or joined model with the given table name,
+// or nil if there is none. The schema of a schema-qualified table may be
+// omitted: "invoices" matches "billing.invoices".
+func (j *Join) modelByTable(table string) *Model {
+	models := []*Model{j.base}
+	for _, je := range j.joins {
+		models = append(models, je.model)
+	}
+	for _, m := range models {
+		if m.Table() == table {
+			return m
+		}
+	}
+	if strings.Contains(table, ".") {
+		return nil
+	}
+	for _, m := range models {
+		if _, name := splitTable(m.Table()); name == table {
+			return m
+		}
+	}
+	return nil
 }
 
 // Pointers returns scan targets from all models in order (base first,
@@ -173,8 +394,28 @@ func (j *Join) Pointers() []any {
 //   - m has a field with fk tag pointing to an existing model
 //   - an existing model has a field with fk tag pointing to m
 //
+// An fk tag without a schema that matches no table in the schema of the
+// tagged model is matched against tables of that name in other schemas.
 // Panics on no match, ambiguous match, or missing/composite PK.
 func (j *Join) resolveFK(m *Model) string {
+	matches := j.fkMatches(m, false)
+	if len(matches) == 0 {
+		matches = j.fkMatches(m, true)
+	}
+
+	if len(matches) == 0 {
+		panic(fmt.Sprintf("Auto: no FK relationship found between %q and existing models", m.table))
+	}
+	if len(matches) > 1 {
+		panic(fmt.Sprintf("Auto: ambiguous FK relationship for %q (%d matches), use Inner/Left/Right instead", m.table, len(matches)))
+	}
+
+	return matches[0]
+}
+
+// fkMatches returns the ON clauses of all FK relationships between m and
+// the models already in the join, see [refersTo] for fallback.
+func (j *Join) fkMatches(m *Model, fallback bool) []string {
 	existing := make([]*Model, 0, 1+len(j.joins))
 	existing = append(existing, j.base)
 	for _, je := range j.joins {
@@ -190,13 +431,12 @@ func (j *Join) resolveFK(m *Model) string {
 		if !hasFk {
 			continue
 		}
-		fkTable := strcase.ToSnake(fkRef)
 		for _, em := range existing {
-			if em.table == fkTable {
+			if refersTo(m.table, fkRef, em.table, fallback) {
 				if len(em.pk) != 1 {
 					panic(fmt.Sprintf("Auto: referenced model %q must have exactly one PK field", em.table))
 				}
-				on := fmt.Sprintf("%s.%s = %s.%s", m.table, f.dbName, em.table, em.pk[0])
+				on := fmt.Sprintf("%s.%s = %s.%s", m.ident(m.table), m.ident(f.dbName), em.ident(em.table), em.ident(em.pk[0]))
 				matches = append(matches, on)
 			}
 		}
@@ -211,26 +451,18 @@ func (j *Join) resolveFK(m *Model) string {
 			if !hasFk {
 				continue
 			}
-			fkTable := strcase.ToSnake(fkRef)
-			if fkTable == m.table {
+			if refersTo(em.table, fkRef, m.table, fallback) {
 				if len(m.pk) != 1 {
 					panic(fmt.Sprintf("Auto: referenced model %q must have exactly one PK field", m.table))
 				}
-				on := fmt.Sprintf("%s.%s = %s.%s", em.table, f.dbName, m.table, m.pk[0])
+				on := fmt.Sprintf("%s.%s = %s.%s", em.ident(em.table), em.ident(f.dbName), m.ident(m.table), m.ident(m.pk[0]))
 				matches = append(matches, on)
 			}
 		}
 		em.mut.RUnlock()
 	}
 
-	if len(matches) == 0 {
-		panic(fmt.Sprintf("Auto: no FK relationship found between %q and existing models", m.table))
-	}
-	if len(matches) > 1 {
-		panic(fmt.Sprintf("Auto: ambiguous FK relationship for %q (%d matches), use Inner/Left/Right instead", m.table, len(matches)))
-	}
-
-	return matches[0]
+	return matches
 }
 
 // collectFields returns field names prefixed with the model's table name.
@@ -240,7 +472,7 @@ func (j *Join) collectFields(m *Model) []string {
 
 	res := make([]string, 0, len(m.fields))
 	for _, f := range m.fields {
-		res = append(res, m.table+"."+f.dbName)
+		res = append(res, m.ident(m.table)+"."+m.ident(f.dbName))
 	}
 	return res
 }
@@ -365,3 +365,23 @@ func TestAutoJoin(t *testing.T) {
 		NewJoin(mUser).Auto(mNoFK)
 	})
 }
+
+func TestJoinWhereConds(t *testing.T) {
+	mUser, mOrder, _ := setupJoinModels(t)
+
+	sql, args, err := NewJoin(mUser).
+		Inner(mOrder, "join_order.user_id = join_user.id").
+		Where("join_user.email LIKE ?", "%@test.com").
+		WhereConds(Eq("Name", "Alice"), Gt("join_order.Total", 50), Eq("missing.Id", 1)).
+		Select()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT join_user.id, join_user.name, join_user.email, join_order.id, join_order.user_id, join_order.total FROM join_user INNER JOIN join_order ON join_order.user_id = join_user.id WHERE (join_user.email LIKE $1) AND join_user.name=$2 AND join_order.total > $3"
+	if sql != want {
+		t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+	}
+	if len(args) != 3 || args[1] != "Alice" || args[2] != 50 {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
@@ -0,0 +1,205 @@
+package norm
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Cursor holds the values of the ORDER BY columns of a row, in the order
+// of the columns. Pass it to [After] or [Before] for keyset pagination.
+//
+//	cursor := norm.Cursor{Values: []any{last.CreatedAt, last.Id}}
+type Cursor struct {
+	// Columns are the db names of the order columns the values were taken
+	// from. If set, they must match the [Order] of the query, so a cursor
+	// cannot be reused with a different ordering. [Model.DecodeCursor]
+	// sets them from the token.
+	Columns []string
+
+	// Values are the values of the order columns.
+	Values []any
+}
+
+// cursorToken is the JSON payload of an encoded cursor.
+type cursorToken struct {
+	Columns []string          `json:"c"`
+	Values  []json.RawMessage `json:"v"`
+}
+
+// EncodeCursor returns an opaque token with the values of the orderBy
+// columns taken from the bound struct, usually the last scanned row of a
+// page. orderBy uses the same format as [Order]; directions are ignored.
+// Decode the token with [Model.DecodeCursor].
+//
+//	token, _ := m.EncodeCursor("CreatedAt DESC, Id DESC")
+func (m *Model) EncodeCursor(orderBy string) (string, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	order := m.parseOrderBy(orderBy)
+	if len(order) == 0 {
+		return "", errors.New("EncodeCursor: no order columns")
+	}
+
+	tok := cursorToken{
+		Columns: make([]string, 0, len(order)),
+		Values:  make([]json.RawMessage, 0, len(order)),
+	}
+	for _, t := range order {
+		raw, err := m.config.JSONMarshal(m.val.FieldByName(t.field.name).Interface())
+		if err != nil {
+			return "", fmt.Errorf("EncodeCursor: field %q: %w", t.field.name, err)
+		}
+		tok.Columns = append(tok.Columns, t.field.dbName)
+		tok.Values = append(tok.Values, raw)
+	}
+
+	data, err := m.config.JSONMarshal(tok)
+	if err != nil {
+		return "", fmt.Errorf("EncodeCursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor parses a token created by [Model.EncodeCursor]. Values are
+// decoded into the Go types of the model's fields, so time and integer
+// columns are bound with their original types.
+//
+//	cursor, err := m.DecodeCursor(r.URL.Query().Get("after"))
+//	sql, args, _ := m.Select(norm.Order("CreatedAt DESC, Id DESC"), norm.After(cursor), norm.Limit(50))
+func (m *modelMeta) DecodeCursor(token string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("DecodeCursor: invalid token: %w", err)
+	}
+
+	var tok cursorToken
+	if err := m.config.JSONUnmarshal(data, &tok); err != nil {
+		return Cursor{}, fmt.Errorf("DecodeCursor: invalid token: %w", err)
+	}
+	if len(tok.Columns) == 0 || len(tok.Columns) != len(tok.Values) {
+		return Cursor{}, errors.New("DecodeCursor: invalid token")
+	}
+
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	res := Cursor{
+		Columns: make([]string, 0, len(tok.Columns)),
+		Values:  make([]any, 0, len(tok.Columns)),
+	}
+	for i, col := range tok.Columns {
+		f, ok := m.fieldByAnyName[col]
+		if !ok {
+			return Cursor{}, m.unknownFieldError("DecodeCursor", col)
+		}
+		ptr := reflect.New(f.valType)
+		if err := m.config.JSONUnmarshal(tok.Values[i], ptr.Interface()); err != nil {
+			return Cursor{}, fmt.Errorf("DecodeCursor: field %q: %w", f.name, err)
+		}
+		res.Columns = append(res.Columns, f.dbName)
+		res.Values = append(res.Values, ptr.Elem().Interface())
+	}
+	return res, nil
+}
+
+// keysetWhere returns the keyset pagination predicate for the order terms,
+// or nil if ks is nil. Columns sorted in the same direction are compared as
+// a row value, (a, b) < ($1, $2); mixed directions expand to
+// (a < $1 OR (a = $1 AND b > $2)).
+// Must be called under m.mut.RLock.
+func (m *modelMeta) keysetWhere(ks *keysetOption, order []orderTerm, prefix string) (*whereOption, error) {
+	if ks == nil {
+		return nil, nil
+	}
+	if len(order) == 0 {
+		return nil, errors.New("Select: After/Before require Order")
+	}
+	values := ks.cursor.Values
+	if len(values) != len(order) {
+		return nil, fmt.Errorf("Select: cursor has %d values, order has %d columns", len(values), len(order))
+	}
+	if err := checkCursorColumns(ks.cursor.Columns, order); err != nil {
+		return nil, err
+	}
+
+	cols := make([]string, len(order))
+	ops := make([]string, len(order))
+	mixed := false
+	for i, t := range order {
+		if t.field == nil {
+			return nil, fmt.Errorf("Select: cannot paginate by select item %q", t.alias)
+		}
+		cols[i] = prefix + t.col
+		ops[i] = ">"
+		if t.desc != ks.before {
+			ops[i] = "<"
+		}
+		if ops[i] != ops[0] {
+			mixed = true
+		}
+	}
+
+	return &whereOption{
+		grouped: true,
+		build: func(b *binder) (string, error) {
+			// values are bound on first use, in the order they appear
+			binds := make([]string, len(values))
+			ref := func(i int) string {
+				if binds[i] == "" {
+					binds[i] = b.bind(values[i])
+					return binds[i]
+				}
+				return b.rebind(binds[i], values[i])
+			}
+
+			if len(cols) == 1 {
+				return cols[0] + " " + ops[0] + " " + ref(0), nil
+			}
+			if !mixed {
+				for i := range binds {
+					ref(i)
+				}
+				return fmt.Sprintf("(%s) %s (%s)", strings.Join(cols, ", "), ops[0], strings.Join(binds, ", ")), nil
+			}
+
+			ors := make([]string, 0, len(cols))
+			for i := range cols {
+				ands := make([]string, 0, i+1)
+				for j := 0; j < i; j++ {
+					ands = append(ands, cols[j]+" = "+ref(j))
+				}
+				ands = append(ands, cols[i]+" "+ops[i]+" "+ref(i))
+				if len(ands) == 1 {
+					ors = append(ors, ands[0])
+				} else {
+					ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+				}
+			}
+			return "(" + strings.Join(ors, " OR ") + ")", nil
+		},
+	}, nil
+}
+
+// checkCursorColumns returns an error if the columns of a cursor are set
+// and differ from the order columns. Select items have no field and never
+// match.
+func checkCursorColumns(cols []string, order []orderTerm) error {
+	if len(cols) == 0 {
+		return nil
+	}
+	want := make([]string, len(order))
+	for i, t := range order {
+		if t.field != nil {
+			want[i] = t.field.dbName
+		}
+	}
+	if strings.Join(cols, ",") != strings.Join(want, ",") {
+		return fmt.Errorf("Select: cursor columns (%s) do not match order (%s)", strings.Join(cols, ", "), strings.Join(want, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package norm
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type KeysetPost struct {
+	Id        int64 `norm:"pk"`
+	Title     string
+	CreatedAt time.Time
+}
+
+func TestKeysetSelect(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&KeysetPost{})
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		want  string
+		nargs int
+	}{
+		{
+			"after desc",
+			[]Option{Order("CreatedAt DESC, Id DESC"), After(Cursor{Values: []any{ts, int64(7)}}), Limit(20)},
+			"SELECT id FROM keyset_post WHERE (created_at, id) < ($1, $2) ORDER BY created_at DESC, id DESC LIMIT 20",
+			2,
+		},
+		{
+			"after asc single column",
+			[]Option{Order("Id"), After(Cursor{Values: []any{int64(7)}})},
+			"SELECT id FROM keyset_post WHERE id > $1 ORDER BY id ASC",
+			1,
+		},
+		{
+			"before reverses order",
+			[]Option{Order("CreatedAt DESC, Id DESC"), Before(Cursor{Values: []any{ts, int64(7)}}), Limit(20)},
+			"SELECT id FROM keyset_post WHERE (created_at, id) > ($1, $2) ORDER BY created_at ASC, id ASC LIMIT 20",
+			2,
+		},
+		{
+			"mixed directions",
+			[]Option{Order("Title ASC, CreatedAt DESC, Id ASC"), After(Cursor{Values: []any{"a", ts, int64(7)}})},
+			"SELECT id FROM keyset_post WHERE (title > $1 OR (title = $1 AND created_at < $2) OR (title = $1 AND created_at = $2 AND id > $3)) ORDER BY title ASC, created_at DESC, id ASC",
+			3,
+		},
+		{
+			"combined with where",
+			[]Option{Where("title <> ?", ""), Order("Id DESC"), After(Cursor{Values: []any{int64(7)}})},
+			"SELECT id FROM keyset_post WHERE (title <> $1) AND id < $2 ORDER BY id DESC",
+			2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := m.Select(append([]Option{Fields("id")}, tt.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.want {
+				t.Errorf("got:\n  %q\nwant:\n  %q", sql, tt.want)
+			}
+			if len(args) != tt.nargs {
+				t.Errorf("unexpected args: %v", args)
+			}
+		})
+	}
+
+	t.Run("no order", func(t *testing.T) {
+		if _, _, err := m.Select(After(Cursor{Values: []any{1}})); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("cursor length mismatch", func(t *testing.T) {
+		if _, _, err := m.Select(Order("CreatedAt, Id"), After(Cursor{Values: []any{ts}})); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestCursorToken(t *testing.T) {
+	n := NewNorm(nil)
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	m, _ := n.M(&KeysetPost{Id: 42, Title: "x", CreatedAt: ts})
+
+	token, err := m.EncodeCursor("CreatedAt DESC, Id DESC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cursor, err := m.DecodeCursor(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cursor.Values) != 2 || strings.Join(cursor.Columns, ",") != "created_at,id" {
+		t.Fatalf("unexpected cursor: %v", cursor)
+	}
+	if got, ok := cursor.Values[0].(time.Time); !ok || !got.Equal(ts) {
+		t.Errorf("created_at: got %#v", cursor.Values[0])
+	}
+	if got, ok := cursor.Values[1].(int64); !ok || got != 42 {
+		t.Errorf("id: got %#v", cursor.Values[1])
+	}
+
+	t.Run("same order", func(t *testing.T) {
+		if _, _, err := m.Select(Order("CreatedAt DESC, Id DESC"), After(cursor)); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("different order", func(t *testing.T) {
+		for _, order := range []string{"Title, Id", "Id, CreatedAt", "CreatedAt"} {
+			if _, _, err := m.Select(Order(order), After(cursor)); err == nil {
+				t.Errorf("expected error for order %q", order)
+			}
+		}
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		if _, err := m.DecodeCursor("not a token!"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
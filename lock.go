@@ -0,0 +1,126 @@
+package norm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lock is a row-locking clause (FOR UPDATE, FOR SHARE, ...) for
+// [Model.Select] and [Join.Lock]. Create it with [ForUpdate], [ForShare]
+// or [ForNoKeyUpdate] and refine it with [Lock.Of], [Lock.SkipLocked] or
+// [Lock.NoWait]. The clause is rendered after LIMIT/OFFSET. Combining a
+// lock with [WithTotal], [GroupBy], [Having], [Agg] or DISTINCT is an error.
+//
+//	m.Select(norm.Where("status = ?", "queued"), norm.Limit(10), norm.ForUpdate().SkipLocked())
+//	// "... WHERE status = $1 LIMIT 10 FOR UPDATE SKIP LOCKED"
+type Lock struct {
+	strength string
+	of       []*Model
+	wait     string // "", "NOWAIT" or "SKIP LOCKED"
+}
+
+// ForUpdate creates a FOR UPDATE lock.
+func ForUpdate() Lock {
+	return Lock{strength: "FOR UPDATE"}
+}
+
+// ForNoKeyUpdate creates a FOR NO KEY UPDATE lock, which does not block
+// inserts of rows referencing the locked ones.
+func ForNoKeyUpdate() Lock {
+	return Lock{strength: "FOR NO KEY UPDATE"}
+}
+
+// ForShare creates a FOR SHARE lock.
+func ForShare() Lock {
+	return Lock{strength: "FOR SHARE"}
+}
+
+// Of restricts the lock to the tables of the given models, typically some
+// of the models of a [Join].
+//
+//	j.Lock(norm.ForUpdate().Of(mOrder))
+//	// "... FOR UPDATE OF orders"
+func (l Lock) Of(models ...*Model) Lock {
+	l.of = append(l.of[:len(l.of):len(l.of)], models...)
+	return l
+}
+
+// SkipLocked makes the query skip rows that are already locked instead of
+// waiting for them. Replaces [Lock.NoWait].
+func (l Lock) SkipLocked() Lock {
+	l.wait = "SKIP LOCKED"
+	return l
+}
+
+// NoWait makes the query fail instead of waiting for locked rows.
+// Replaces [Lock.SkipLocked].
+func (l Lock) NoWait() Lock {
+	l.wait = "NOWAIT"
+	return l
+}
+
+func (l Lock) Type() OptionType { return LockOption }
+func (l Lock) Value() any       { return l }
+
+// sql renders the clause with a leading space, or "" for the zero Lock.
+func (l Lock) sql() string {
+	if l.strength == "" {
+		return ""
+	}
+
+	sql := " " + l.strength
+	if len(l.of) > 0 {
+		tables := make([]string, len(l.of))
+		for i, m := range l.of {
+			_, table := splitTable(m.Table())
+			tables[i] = m.ident(table)
+		}
+		sql += " OF " + strings.Join(tables, ", ")
+	}
+	if l.wait != "" {
+		sql += " " + l.wait
+	}
+	return sql
+}
+
+// check reports an error if d does not support the lock, or if the lock
+// is combined with a select feature PostgreSQL does not allow with row
+// locking: window functions such as [WithTotal], GROUP BY, HAVING,
+// aggregates and DISTINCT.
+func (l Lock) check(d Dialect, co ComposedOptions) error {
+	if l.strength == "" {
+		return nil
+	}
+	if err := unsupported("Select", d, FeatureLock); err != nil {
+		return err
+	}
+	if l.strength == "FOR NO KEY UPDATE" {
+		if err := unsupported("Select", d, FeatureNoKeyUpdate); err != nil {
+			return err
+		}
+	}
+	if l.wait != "" {
+		if err := unsupported("Select", d, FeatureSkipLocked); err != nil {
+			return err
+		}
+	}
+
+	var with string
+	switch {
+	case co.Total != nil:
+		with = "WithTotal"
+	case len(co.GroupBy) > 0:
+		with = "GroupBy"
+	case co.Having != nil:
+		with = "Having"
+	case len(co.Aggs) > 0:
+		with = "Agg"
+	case len(co.DistinctOn) > 0:
+		with = "DistinctOn"
+	case co.Distinct:
+		with = "Distinct"
+	default:
+		return nil
+	}
+	return fmt.Errorf("Select: %s cannot be combined with %s", l.strength, with)
+}
@@ -0,0 +1,98 @@
+package norm
+
+import "testing"
+
+func TestSelectLock(t *testing.T) {
+	m := newTestModel()
+
+	tests := []struct {
+		name string
+		lock Lock
+		want string
+	}{
+		{"for update", ForUpdate(), " FOR UPDATE"},
+		{"for share", ForShare(), " FOR SHARE"},
+		{"for no key update nowait", ForNoKeyUpdate().NoWait(), " FOR NO KEY UPDATE NOWAIT"},
+		{"skip locked", ForUpdate().SkipLocked(), " FOR UPDATE SKIP LOCKED"},
+		{"last modifier wins", ForUpdate().SkipLocked().NoWait(), " FOR UPDATE NOWAIT"},
+		{"of", ForUpdate().Of(m), " FOR UPDATE OF model_test_struct"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := m.Select(Fields("id"), Where("age > ?", 1), Limit(10), Offset(5), tt.lock)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := "SELECT id FROM model_test_struct WHERE age > $1 LIMIT 10 OFFSET 5" + tt.want
+			if sql != want {
+				t.Errorf("got %q", sql)
+			}
+		})
+	}
+
+	t.Run("select by pk", func(t *testing.T) {
+		sql, _, err := m.SelectByPK(Fields("id"), ForUpdate())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql != "SELECT id FROM model_test_struct WHERE id=$1 FOR UPDATE" {
+			t.Errorf("got %q", sql)
+		}
+	})
+}
+
+func TestJoinLock(t *testing.T) {
+	mUser, mOrder, _ := setupJoinModels(t)
+
+	sql, _, err := NewJoin(mUser).
+		Inner(mOrder, "join_order.user_id = join_user.id").
+		Limit(1).
+		Lock(ForUpdate().Of(mOrder).SkipLocked()).
+		Select()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT join_user.id, join_user.name, join_user.email, join_order.id, join_order.user_id, join_order.total FROM join_user INNER JOIN join_order ON join_order.user_id = join_user.id LIMIT 1 FOR UPDATE OF join_order SKIP LOCKED"
+	if sql != want {
+		t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+	}
+}
+
+func TestLockConflicts(t *testing.T) {
+	m := newTestModel()
+	var total int64
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"total", []Option{WithTotal(&total)}, "Select: FOR UPDATE cannot be combined with WithTotal"},
+		{"group by", []Option{GroupBy("Name")}, "Select: FOR UPDATE cannot be combined with GroupBy"},
+		{"agg", []Option{Agg("count", "*", "n")}, "Select: FOR UPDATE cannot be combined with Agg"},
+		{"distinct", []Option{Distinct()}, "Select: FOR UPDATE cannot be combined with Distinct"},
+		{"distinct on", []Option{DistinctOn("Name")}, "Select: FOR UPDATE cannot be combined with DistinctOn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := m.Select(append(tt.opts, ForUpdate())...)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("join distinct", func(t *testing.T) {
+		mUser, mOrder, _ := setupJoinModels(t)
+		_, _, err := NewJoin(mUser).
+			Inner(mOrder, "join_order.user_id = join_user.id").
+			Distinct().
+			Lock(ForShare()).
+			Select()
+		if err == nil || err.Error() != "Select: FOR SHARE cannot be combined with Distinct" {
+			t.Errorf("got %v", err)
+		}
+	})
+}
@@ -17,7 +17,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/iancoleman/strcase"
 	"github.com/juggle73/norm/v4"
 )
 
@@ -145,6 +144,12 @@ func (m *Migrate) resolvePK(table string) string {
 
 // ── SQL generation ──────────────────────────────────────────────────────────
 
+// ident returns a table or column name as it appears in DDL, quoted like
+// in the query builders, see [norm.Norm.QuoteIdent].
+func (m *Migrate) ident(name string) string {
+	return m.norm.QuoteIdent(name)
+}
+
 // CreateTableSQL returns a CREATE TABLE IF NOT EXISTS statement for a
 // registered table. Includes PRIMARY KEY, NOT NULL, UNIQUE, DEFAULT,
 // and FOREIGN KEY constraints.
@@ -159,7 +164,7 @@ func (m *Migrate) CreateTableSQL(table string) string {
 	var fks []string
 
 	for _, f := range fields {
-		col := f.DbName() + " " + m.pgType(f)
+		col := m.ident(f.DbName()) + " " + m.pgType(f)
 
 		_, isPK := f.Tag("pk")
 		_, notNull := f.Tag("notnull")
@@ -177,16 +182,15 @@ func (m *Migrate) CreateTableSQL(table string) string {
 		}
 
 		if isPK {
-			pks = append(pks, f.DbName())
+			pks = append(pks, m.ident(f.DbName()))
 		}
 
-		if fkTable, ok := f.Tag("fk"); ok {
-			refTable := strcase.ToSnake(fkTable)
+		if refTable, ok := m.norm.FKTable(table, f); ok {
 			refPK := m.resolvePK(refTable)
 			if refPK != "" {
 				fks = append(fks, fmt.Sprintf(
 					"FOREIGN KEY (%s) REFERENCES %s(%s)",
-					f.DbName(), refTable, refPK,
+					m.ident(f.DbName()), m.ident(refTable), m.ident(refPK),
 				))
 			}
 		}
@@ -200,12 +204,12 @@ func (m *Migrate) CreateTableSQL(table string) string {
 	cols = append(cols, fks...)
 
 	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n    %s\n);",
-		table, strings.Join(cols, ",\n    "))
+		m.ident(table), strings.Join(cols, ",\n    "))
 }
 
 // addColumnSQL returns an ALTER TABLE ADD COLUMN statement.
 func (m *Migrate) addColumnSQL(table string, f *norm.Field) string {
-	col := f.DbName() + " " + m.pgType(f)
+	col := m.ident(f.DbName()) + " " + m.pgType(f)
 
 	_, notNull := f.Tag("notnull")
 	if notNull {
@@ -222,15 +226,14 @@ func (m *Migrate) addColumnSQL(table string, f *norm.Field) string {
 		col += " UNIQUE"
 	}
 
-	if fkTable, ok := f.Tag("fk"); ok {
-		refTable := strcase.ToSnake(fkTable)
+	if refTable, ok := m.norm.FKTable(table, f); ok {
 		refPK := m.resolvePK(refTable)
 		if refPK != "" {
-			col += fmt.Sprintf(" REFERENCES %s(%s)", refTable, refPK)
+			col += fmt.Sprintf(" REFERENCES %s(%s)", m.ident(refTable), m.ident(refPK))
 		}
 	}
 
-	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;", table, col)
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;", m.ident(table), col)
 }
 
 // ── Sync ────────────────────────────────────────────────────────────────────
@@ -325,7 +328,7 @@ func (m *Migrate) Diff(ctx context.Context) (string, error) {
 			if normalizeType(existing.dataType) != normalizeType(expectedType) {
 				stmts = append(stmts, fmt.Sprintf(
 					"ALTER TABLE %s ALTER COLUMN %s TYPE %s;",
-					table, f.DbName(), expectedType,
+					m.ident(table), m.ident(f.DbName()), expectedType,
 				))
 			}
 
@@ -339,12 +342,12 @@ func (m *Migrate) Diff(ctx context.Context) (string, error) {
 			if wantNotNull && existing.isNullable {
 				stmts = append(stmts, fmt.Sprintf(
 					"ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;",
-					table, f.DbName(),
+					m.ident(table), m.ident(f.DbName()),
 				))
 			} else if !wantNotNull && !existing.isNullable && !existing.isPK {
 				stmts = append(stmts, fmt.Sprintf(
 					"ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;",
-					table, f.DbName(),
+					m.ident(table), m.ident(f.DbName()),
 				))
 			}
 		}
@@ -354,7 +357,7 @@ func (m *Migrate) Diff(ctx context.Context) (string, error) {
 			if !expectedSet[col.name] {
 				stmts = append(stmts, fmt.Sprintf(
 					"ALTER TABLE %s DROP COLUMN %s;",
-					table, col.name,
+					m.ident(table), m.ident(col.name),
 				))
 			}
 		}
@@ -365,21 +368,36 @@ func (m *Migrate) Diff(ctx context.Context) (string, error) {
 
 // ── DB schema queries ───────────────────────────────────────────────────────
 
+// schemaFilter matches information_schema rows of the schema bound to $1,
+// or of the current schema if it is empty.
+const schemaFilter = "COALESCE(NULLIF($1, ''), current_schema())"
+
+// splitTable splits a possibly schema-qualified table name into the schema
+// ("" if none) and the table name, as bind values for the schema queries.
+func splitTable(table string) (schema, name string) {
+	if i := strings.IndexByte(table, '.'); i >= 0 {
+		return table[:i], table[i+1:]
+	}
+	return "", table
+}
+
 func (m *Migrate) tableExists(ctx context.Context, table string) (bool, error) {
+	schema, name := splitTable(table)
 	var exists bool
 	err := m.db.QueryRowContext(ctx,
-		"SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name=$1)",
-		table,
+		"SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema="+schemaFilter+" AND table_name=$2)",
+		schema, name,
 	).Scan(&exists)
 	return exists, err
 }
 
 func (m *Migrate) queryColumns(ctx context.Context, table string) ([]dbColumn, error) {
+	schema, name := splitTable(table)
 	rows, err := m.db.QueryContext(ctx,
 		`SELECT column_name, data_type, is_nullable
 		 FROM information_schema.columns
-		 WHERE table_name=$1
-		 ORDER BY ordinal_position`, table)
+		 WHERE table_schema=`+schemaFilter+` AND table_name=$2
+		 ORDER BY ordinal_position`, schema, name)
 	if err != nil {
 		return nil, fmt.Errorf("query columns for %s: %w", table, err)
 	}
@@ -433,14 +451,16 @@ func (m *Migrate) queryColumns(ctx context.Context, table string) ([]dbColumn, e
 }
 
 func (m *Migrate) queryConstraintColumns(ctx context.Context, table, constraintType string) (map[string]bool, error) {
+	schema, name := splitTable(table)
 	rows, err := m.db.QueryContext(ctx,
 		`SELECT kcu.column_name
 		 FROM information_schema.table_constraints tc
 		 JOIN information_schema.key_column_usage kcu
 		     ON tc.constraint_name = kcu.constraint_name
 		     AND tc.table_schema = kcu.table_schema
-		 WHERE tc.constraint_type = $1
-		     AND tc.table_name = $2`, constraintType, table)
+		 WHERE tc.table_schema = `+schemaFilter+`
+		     AND tc.table_name = $2
+		     AND tc.constraint_type = $3`, schema, name, constraintType)
 	if err != nil {
 		return nil, fmt.Errorf("query %s for %s: %w", constraintType, table, err)
 	}
@@ -458,6 +478,7 @@ func (m *Migrate) queryConstraintColumns(ctx context.Context, table, constraintT
 }
 
 func (m *Migrate) queryForeignKeys(ctx context.Context, table string) (map[string]string, error) {
+	schema, name := splitTable(table)
 	rows, err := m.db.QueryContext(ctx,
 		`SELECT kcu.column_name, ccu.table_name
 		 FROM information_schema.table_constraints tc
@@ -468,7 +489,8 @@ func (m *Migrate) queryForeignKeys(ctx context.Context, table string) (map[strin
 		     ON ccu.constraint_name = tc.constraint_name
 		     AND ccu.table_schema = tc.table_schema
 		 WHERE tc.constraint_type = 'FOREIGN KEY'
-		     AND tc.table_name = $1`, table)
+		     AND tc.table_schema = `+schemaFilter+`
+		     AND tc.table_name = $2`, schema, name)
 	if err != nil {
 		return nil, fmt.Errorf("query FK for %s: %w", table, err)
 	}
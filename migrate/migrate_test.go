@@ -55,7 +55,7 @@ func TestCreateTableSQL(t *testing.T) {
 	mig := newMigrate(&User{})
 	sql := mig.CreateTableSQL("user")
 
-	assertContains(t, sql, "CREATE TABLE IF NOT EXISTS user")
+	assertContains(t, sql, `CREATE TABLE IF NOT EXISTS "user"`)
 	assertContains(t, sql, "id integer NOT NULL")
 	assertContains(t, sql, "name text NOT NULL")
 	assertContains(t, sql, "email text UNIQUE")
@@ -68,7 +68,7 @@ func TestCreateTableSQL_FK(t *testing.T) {
 	sql := mig.CreateTableSQL("order")
 
 	assertContains(t, sql, "user_id integer NOT NULL")
-	assertContains(t, sql, "FOREIGN KEY (user_id) REFERENCES user(id)")
+	assertContains(t, sql, `FOREIGN KEY (user_id) REFERENCES "user"(id)`)
 	assertContains(t, sql, "PRIMARY KEY (id)")
 }
 
@@ -144,7 +144,7 @@ func TestAddColumnSQL(t *testing.T) {
 	}
 
 	sql := mig.addColumnSQL("user", emailField)
-	assertContains(t, sql, "ALTER TABLE user ADD COLUMN IF NOT EXISTS email text UNIQUE;")
+	assertContains(t, sql, `ALTER TABLE "user" ADD COLUMN IF NOT EXISTS email text UNIQUE;`)
 
 	// FK column
 	orderFields := mig.norm.FieldsByTable("order")
@@ -160,7 +160,7 @@ func TestAddColumnSQL(t *testing.T) {
 	}
 
 	sql = mig.addColumnSQL("order", userIdField)
-	assertContains(t, sql, "ALTER TABLE order ADD COLUMN IF NOT EXISTS user_id integer NOT NULL REFERENCES user(id);")
+	assertContains(t, sql, `ALTER TABLE "order" ADD COLUMN IF NOT EXISTS user_id integer NOT NULL REFERENCES "user"(id);`)
 }
 
 func TestPgType(t *testing.T) {
@@ -267,3 +267,55 @@ func assertContains(t *testing.T, s, substr string) {
 		t.Errorf("expected %q to contain %q", s, substr)
 	}
 }
+
+func TestCreateTableSQL_QuoteIdentifiers(t *testing.T) {
+	n := norm.NewNorm(&norm.Config{QuoteIdentifiers: true})
+	n.M(&User{})
+	n.M(&Order{})
+	mig := New(nil, n)
+	sql := mig.CreateTableSQL("order")
+
+	assertContains(t, sql, `CREATE TABLE IF NOT EXISTS "order"`)
+	assertContains(t, sql, `"user_id" integer NOT NULL`)
+	assertContains(t, sql, `PRIMARY KEY ("id")`)
+	assertContains(t, sql, `FOREIGN KEY ("user_id") REFERENCES "user"("id")`)
+}
+
+type Invoice struct {
+	Id     int `norm:"pk"`
+	Amount int
+}
+
+type Payment struct {
+	Id        int `norm:"pk"`
+	InvoiceId int `norm:"fk=billing.Invoice,notnull"`
+	OrderId   int `norm:"fk=Order"`
+}
+
+func TestCreateTableSQL_Schema(t *testing.T) {
+	n := norm.NewNorm(nil)
+	n.AddModel(&Invoice{}, "billing.invoice")
+	n.AddModel(&Order{}, "sales.order")
+	n.AddModel(&Payment{}, "sales.payment")
+	mig := New(nil, n)
+	sql := mig.CreateTableSQL("sales.payment")
+
+	assertContains(t, sql, "CREATE TABLE IF NOT EXISTS sales.payment")
+	assertContains(t, sql, "FOREIGN KEY (invoice_id) REFERENCES billing.invoice(id)")
+	assertContains(t, sql, `FOREIGN KEY (order_id) REFERENCES sales."order"(id)`)
+}
+
+func TestSplitTable(t *testing.T) {
+	tests := []struct {
+		table, schema, name string
+	}{
+		{"users", "", "users"},
+		{"billing.invoice", "billing", "invoice"},
+	}
+	for _, tt := range tests {
+		schema, name := splitTable(tt.table)
+		if schema != tt.schema || name != tt.name {
+			t.Errorf("splitTable(%q) = %q, %q", tt.table, schema, name)
+		}
+	}
+}
@@ -10,6 +10,11 @@ import (
 	"github.com/iancoleman/strcase"
 )
 
+// ErrUnknownField is reported in [Config.Strict] mode and by
+// [Model.BuildConditionsE] when a field name does not exist in the model.
+// The error message includes the method, the name and the table.
+var ErrUnknownField = errors.New("unknown field")
+
 // modelMeta caches struct reflection metadata (field names, types, tags).
 // It is created once per struct type and shared across all [Model] instances.
 // Thread-safe for concurrent reads.
@@ -20,8 +25,10 @@ type modelMeta struct {
 	fieldByAnyName map[string]*Field
 	mut            sync.RWMutex
 
-	config *Config
-	pk     []string
+	config     *Config
+	pk         []string
+	softDelete string // db name of the softdelete column, empty if none
+	version    string // db name of the version column, empty if none
 }
 
 // Model binds cached metadata to a specific struct instance. It provides
@@ -32,7 +39,8 @@ type modelMeta struct {
 // own Model via [Norm.M].
 type Model struct {
 	*modelMeta
-	val reflect.Value
+	val      reflect.Value
+	snapshot map[string]any // field name → value captured by Snapshot
 }
 
 // newModelMeta creates a new modelMeta instance with the given config.
@@ -44,7 +52,8 @@ func newModelMeta(config *Config) *modelMeta {
 
 // Parse extracts field metadata from obj and stores it in the modelMeta.
 // obj must be a struct or pointer to struct. If table is empty, the table
-// name is derived from the struct name in snake_case.
+// name is derived from the struct name in snake_case. A table without a
+// schema is qualified with [Config.DefaultSchema].
 func (m *modelMeta) Parse(obj any, table string) error {
 	val := reflect.Indirect(reflect.ValueOf(obj))
 	if val.Kind() != reflect.Struct {
@@ -59,8 +68,9 @@ func (m *modelMeta) Parse(obj any, table string) error {
 	} else {
 		m.table = table
 	}
-	if !isValidIdentifier(m.table) {
-		panic(fmt.Sprintf("invalid table name %q: must contain only [a-zA-Z0-9_]", m.table))
+	m.table = m.config.qualify(m.table)
+	if !isValidTableName(m.table) {
+		panic(fmt.Sprintf("invalid table name %q: must be [schema.]name with only [a-zA-Z0-9_]", m.table))
 	}
 	m.fields = make([]*Field, 0)
 	m.fieldByAnyName = make(map[string]*Field)
@@ -68,6 +78,8 @@ func (m *modelMeta) Parse(obj any, table string) error {
 	m.valType = val.Type()
 
 	m.pk = make([]string, 0)
+	m.softDelete = ""
+	m.version = ""
 
 	m.parseFields(val.Type())
 
@@ -115,16 +127,118 @@ func (m *modelMeta) parseFields(t reflect.Type) {
 		if _, hasPk := tagValues["pk"]; hasPk {
 			m.pk = append(m.pk, field.dbName)
 		}
+
+		if _, ok := tagValues["version"]; ok {
+			if m.version != "" {
+				panic(fmt.Sprintf("multiple version fields in %s", t.Name()))
+			}
+			m.version = field.dbName
+		}
+
+		if _, ok := tagValues["softdelete"]; ok {
+			if m.softDelete != "" {
+				panic(fmt.Sprintf("multiple softdelete fields in %s", t.Name()))
+			}
+			m.softDelete = field.dbName
+		}
 	}
 }
 
 // filteredFields returns fields filtered by Exclude/Fields options.
+// Names in the options are resolved to db names; in [Config.Strict] mode an
+// unknown name is reported as an error prefixed with method.
 // Must be called under m.mut.RLock.
-func (m *modelMeta) filteredFields(opts ...Option) ([]*Field, ComposedOptions) {
+func (m *modelMeta) filteredFields(method string, opts ...Option) ([]*Field, ComposedOptions, error) {
 	co := ComposeOptions(opts...)
+	if err := m.resolveFieldOptions(method, &co); err != nil {
+		return nil, co, err
+	}
+	// Only aggregates are selected unless fields are given
+	if len(co.Aggs) > 0 && len(co.Fields) == 0 {
+		return nil, co, nil
+	}
+	return filterFields(m.fields, co), co, nil
+}
 
-	res := make([]*Field, 0, len(m.fields))
-	for _, f := range m.fields {
+// mustFilteredFields is like filteredFields for methods that cannot return
+// an error; it panics on unknown names in [Config.Strict] mode.
+// Must be called under m.mut.RLock.
+func (m *modelMeta) mustFilteredFields(method string, opts ...Option) ([]*Field, ComposedOptions) {
+	ff, co, err := m.filteredFields(method, opts...)
+	if err != nil {
+		panic(err.Error())
+	}
+	return ff, co
+}
+
+// resolveFieldOptions replaces the names in co.Exclude, co.Fields and
+// co.GroupBy, given in any format, with db names. Unknown names in Exclude
+// and Fields are kept as they are, so they match nothing, unless
+// [Config.Strict] is set; unknown GroupBy names are always an error.
+// Without Fields the grouped fields are selected.
+// Must be called under m.mut.RLock.
+func (m *modelMeta) resolveFieldOptions(method string, co *ComposedOptions) error {
+	var err error
+	if co.Exclude, err = m.resolveNames(method, co.Exclude); err != nil {
+		return err
+	}
+	if co.Fields, err = m.resolveNames(method, co.Fields); err != nil {
+		return err
+	}
+
+	for i, name := range co.GroupBy {
+		f, ok := m.fieldByAnyName[strings.TrimSpace(name)]
+		if !ok {
+			return m.unknownFieldError(method, strings.TrimSpace(name))
+		}
+		co.GroupBy[i] = f.dbName
+	}
+	for i, name := range co.DistinctOn {
+		f, ok := m.fieldByAnyName[strings.TrimSpace(name)]
+		if !ok {
+			return m.unknownFieldError(method, strings.TrimSpace(name))
+		}
+		co.DistinctOn[i] = f.dbName
+	}
+	if len(co.Fields) == 0 {
+		co.Fields = co.GroupBy
+	}
+	return nil
+}
+
+// resolveNames maps field names in any format to db names.
+// Must be called under m.mut.RLock.
+func (m *modelMeta) resolveNames(method string, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return names, nil
+	}
+
+	res := make([]string, len(names))
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		f, ok := m.fieldByAnyName[name]
+		if !ok {
+			if name != "" && m.config.Strict {
+				return nil, m.unknownFieldError(method, name)
+			}
+			res[i] = name
+			continue
+		}
+		res[i] = f.dbName
+	}
+	return res, nil
+}
+
+// unknownFieldError reports a field name that does not exist in the model.
+func (m *modelMeta) unknownFieldError(method, name string) error {
+	return fmt.Errorf("%s: %w %q in model %q", method, ErrUnknownField, name, m.table)
+}
+
+// filterFields returns the subset of fields that pass the Exclude/Fields
+// options in co.
+func filterFields(fields []*Field, co ComposedOptions) []*Field {
+	res := make([]*Field, 0, len(fields))
+	for _, f := range fields {
 		if has(co.Exclude, f.dbName) {
 			continue
 		}
@@ -133,8 +247,7 @@ func (m *modelMeta) filteredFields(opts ...Option) ([]*Field, ComposedOptions) {
 		}
 		res = append(res, f)
 	}
-
-	return res, co
+	return res
 }
 
 // Fields returns a comma-separated list of column names in snake_case.
@@ -147,11 +260,11 @@ func (m *modelMeta) Fields(opts ...Option) string {
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
-	ff, co := m.filteredFields(opts...)
+	ff, co := m.mustFilteredFields("Fields", opts...)
 
 	res := make([]string, 0, len(ff))
 	for _, f := range ff {
-		res = append(res, co.Prefix+f.dbName)
+		res = append(res, co.Prefix+m.ident(f.dbName))
 	}
 
 	return strings.Join(res, ", ")
@@ -166,16 +279,28 @@ func (m *modelMeta) UpdateFields(opts ...Option) (string, int) {
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
-	ff, _ := m.filteredFields(opts...)
+	ff, _ := m.mustFilteredFields("UpdateFields", opts...)
 
 	res := make([]string, 0, len(ff))
 	for i, f := range ff {
-		res = append(res, fmt.Sprintf("%s=$%d", f.dbName, i+1))
+		res = append(res, m.ident(f.dbName)+"="+m.config.Dialect.Placeholder(i+1))
 	}
 
 	return strings.Join(res, ", "), len(ff) + 1
 }
 
+// BuildWhere is like the package-level [BuildWhere] with the placeholders
+// of the model's [Config.Dialect]; slices follow [Config.SlicesAsArrays].
+//
+//	set, nextBind := m.UpdateFields(norm.Exclude("id"))
+//	whereStr, whereArgs := m.BuildWhere(nextBind, "id = ?", user.Id)
+//	// SQLite: set = "name=?, email=?", whereStr = "id = ?"
+func (m *modelMeta) BuildWhere(startBind int, where string, args ...any) (string, []any) {
+	b := m.newBinder()
+	b.next = startBind
+	return buildWhere(b, where, args)
+}
+
 // Binds returns a comma-separated list of bind placeholders ($1, $2, ...).
 // Supports [Exclude] and [Fields] options.
 //
@@ -185,11 +310,11 @@ func (m *modelMeta) Binds(opts ...Option) string {
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
-	ff, _ := m.filteredFields(opts...)
+	ff, _ := m.mustFilteredFields("Binds", opts...)
 
 	res := make([]string, 0, len(ff))
 	for i := range ff {
-		res = append(res, fmt.Sprintf("$%d", i+1))
+		res = append(res, m.config.Dialect.Placeholder(i+1))
 	}
 
 	return strings.Join(res, ", ")
@@ -198,7 +323,9 @@ func (m *modelMeta) Binds(opts ...Option) string {
 // Pointers returns a slice of pointers to the bound struct's fields,
 // suitable for passing to rows.Scan(). Struct fields (except time.Time)
 // are wrapped in a JSON scanner automatically.
-// Supports [Exclude], [Fields], and [AddTargets] options.
+// Supports [Exclude], [Fields], [GroupBy], [Agg], [WithTotal] and
+// [AddTargets] options; targets follow the order of the select list built
+// by [Model.Select] (fields, aggregates, total), extra targets come last.
 //
 //	err := row.Scan(m.Pointers()...)
 //	err := row.Scan(m.Pointers(norm.AddTargets(&totalCount))...)
@@ -206,9 +333,9 @@ func (m *Model) Pointers(opts ...Option) []any {
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
-	ff, co := m.filteredFields(opts...)
+	ff, co := m.mustFilteredFields("Pointers", opts...)
 
-	res := make([]any, 0, len(ff)+len(co.AddTargets))
+	res := make([]any, 0, len(ff)+len(co.Aggs)+len(co.AddTargets)+1)
 	for _, f := range ff {
 		ptr := m.val.FieldByName(f.name).Addr().Interface()
 		if f.IsJSON() {
@@ -218,6 +345,14 @@ func (m *Model) Pointers(opts ...Option) []any {
 		}
 	}
 
+	for _, a := range co.Aggs {
+		res = append(res, a.pointer())
+	}
+
+	if co.Total != nil {
+		res = append(res, co.Total)
+	}
+
 	for _, p := range co.AddTargets {
 		res = append(res, p)
 	}
@@ -242,20 +377,15 @@ func (m *Model) Values(opts ...Option) []any {
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
-	ff, _ := m.filteredFields(opts...)
+	ff, _ := m.mustFilteredFields("Values", opts...)
 
 	res := make([]any, 0, len(ff))
 	for _, f := range ff {
-		val := m.val.FieldByName(f.name).Interface()
-		if f.IsJSON() {
-			b, err := m.config.JSONMarshal(val)
-			if err != nil {
-				panic(fmt.Sprintf("Values: json marshal field %q: %v", f.name, err))
-			}
-			res = append(res, b)
-		} else {
-			res = append(res, val)
+		val, err := m.fieldValue(m.val, f)
+		if err != nil {
+			panic(fmt.Sprintf("Values: %v", err))
 		}
+		res = append(res, val)
 	}
 
 	return res
@@ -263,7 +393,10 @@ func (m *Model) Values(opts ...Option) []any {
 
 // Select builds a full SELECT query from the bound model.
 // Returns the SQL string, positional arguments, and any error.
-// Supports [Exclude], [Fields], [Prefix], [Where], [Order], [Limit], [Offset] options.
+// Supports [Exclude], [Fields], [Prefix], [Where], [WhereConds], [Order],
+// [Limit], [Offset], [After], [Before], [WithTotal], [GroupBy], [Having],
+// [Agg] and [Lock] options.
+// Soft-deleted rows are filtered out, see [WithDeleted] and [OnlyDeleted].
 //
 //	sql, args, _ := m.Select(
 //	    norm.Where("active = ?", true),
@@ -272,164 +405,594 @@ func (m *Model) Values(opts ...Option) []any {
 //	)
 //	// "SELECT id, name, email FROM users WHERE active=$1 ORDER BY name DESC LIMIT 10"
 func (m *Model) Select(opts ...Option) (string, []any, error) {
-	co := ComposeOptions(opts...)
-
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
-	ff, _ := m.filteredFields(opts...)
+	ff, co, err := m.filteredFields("Select", opts...)
+	if err != nil {
+		return "", nil, err
+	}
 
-	cols := make([]string, 0, len(ff))
+	return m.selectSQL(ff, co)
+}
+
+// selectSQL builds a SELECT query for the given fields. preds are extra
+// WHERE predicates combined with the [Where] option.
+// Must be called under m.mut.RLock.
+func (m *Model) selectSQL(ff []*Field, co ComposedOptions, preds ...*whereOption) (string, []any, error) {
+	if err := co.Lock.check(m.config.Dialect, co); err != nil {
+		return "", nil, err
+	}
+
+	cols := make([]string, 0, len(ff)+len(co.Aggs)+1)
 	for _, f := range ff {
-		cols = append(cols, co.Prefix+f.dbName)
+		cols = append(cols, co.Prefix+m.ident(f.dbName))
+	}
+	for _, a := range co.Aggs {
+		col, _, err := m.aggSQL(a, co.Prefix)
+		if err != nil {
+			return "", nil, err
+		}
+		cols = append(cols, col)
+	}
+	if co.Total != nil {
+		cols = append(cols, "count(*) OVER()")
 	}
 
-	sql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ", "), m.table)
+	order := m.parseOrderBy(co.OrderBy, m.aggAliases(co.Aggs)...)
 
-	var args []any
+	distinct, err := m.distinctSQL(co, order)
+	if err != nil {
+		return "", nil, err
+	}
 
-	if co.Where != nil {
-		whereStr, _ := co.Where.Build(1)
-		sql += " WHERE " + whereStr
-		args = append(args, co.Where.Args...)
+	keyset, err := m.keysetWhere(co.Keyset, order, co.Prefix)
+	if err != nil {
+		return "", nil, err
 	}
 
-	if co.OrderBy != "" {
-		sql += " ORDER BY " + m.orderBySQL(co.OrderBy)
+	b := m.newBinder()
+	sql, err := m.config.withSQL(b, co.With)
+	if err != nil {
+		return "", nil, err
 	}
+	sql += fmt.Sprintf("SELECT %s%s FROM %s", distinct, strings.Join(cols, ", "), m.ident(m.table))
 
-	if co.Limit > 0 {
-		sql += fmt.Sprintf(" LIMIT %d", co.Limit)
+	where, err := m.selectWhere(b, co, preds, keyset)
+	if err != nil {
+		return "", nil, err
 	}
-	if co.Offset > 0 {
-		sql += fmt.Sprintf(" OFFSET %d", co.Offset)
+	sql += where
+
+	if len(co.GroupBy) > 0 {
+		group := make([]string, len(co.GroupBy))
+		for i, col := range co.GroupBy {
+			group[i] = co.Prefix + m.ident(col)
+		}
+		sql += " GROUP BY " + strings.Join(group, ", ")
 	}
 
-	return sql, args, nil
+	if co.Having != nil {
+		having, err := co.Having.render(b)
+		if err != nil {
+			return "", nil, err
+		}
+		sql += " HAVING " + having
+	}
+
+	if len(order) > 0 {
+		terms := make([]string, 0, len(order))
+		for _, t := range order {
+			if co.Keyset != nil && co.Keyset.before {
+				t.desc = !t.desc
+			}
+			terms = append(terms, t.sql(""))
+		}
+		sql += " ORDER BY " + strings.Join(terms, ", ")
+	}
+
+	if lo := m.config.Dialect.LimitOffset(co.Limit, co.Offset); lo != "" {
+		sql += " " + lo
+	}
+
+	sql += co.Lock.sql()
+
+	return sql, b.args, nil
+}
+
+// distinctSQL renders the DISTINCT or DISTINCT ON (...) select modifier
+// with a trailing space, or "" if neither is set.
+func (m *Model) distinctSQL(co ComposedOptions, order []orderTerm) (string, error) {
+	if !co.Distinct {
+		return "", nil
+	}
+	if len(co.DistinctOn) == 0 {
+		return "DISTINCT ", nil
+	}
+	if err := unsupported("Select", m.config.Dialect, FeatureDistinctOn); err != nil {
+		return "", err
+	}
+
+	on := make([]string, len(co.DistinctOn))
+	for i, col := range co.DistinctOn {
+		on[i] = co.Prefix + m.ident(col)
+	}
+	terms := make([]string, len(order))
+	for i, t := range order {
+		terms[i] = t.col
+		if t.field != nil {
+			terms[i] = co.Prefix + t.col
+		}
+	}
+	if err := checkDistinctOn(on, terms); err != nil {
+		return "", err
+	}
+	return "DISTINCT ON (" + strings.Join(on, ", ") + ") ", nil
+}
+
+// checkDistinctOn reports an error unless the leading ORDER BY expressions
+// are the DISTINCT ON expressions, in any order. PostgreSQL rejects such
+// queries.
+func checkDistinctOn(on, order []string) error {
+	n := min(len(on), len(order))
+	for i, col := range order[:n] {
+		if !has(on, col) || has(order[:i], col) {
+			return fmt.Errorf("Select: ORDER BY must start with the DISTINCT ON expressions (%s)", strings.Join(on, ", "))
+		}
+	}
+	return nil
+}
+
+// selectWhere renders the WHERE clause of a SELECT: preds, the [Where] and
+// [WhereConds] options, the keyset predicate and the soft-delete filter.
+// Must be called under m.mut.RLock.
+func (m *Model) selectWhere(b *binder, co ComposedOptions, preds []*whereOption, keyset *whereOption) (string, error) {
+	return renderWhere(b, append(preds, m.structWhere(co.Where), m.whereConds("Select", co, co.Prefix),
+		keyset, m.softDeleteWhere(co.Prefix, co.Deleted))...)
+}
+
+// Count builds a SELECT count(*) query with the same WHERE clause as
+// [Model.Select] would produce. Order, limit, cursor, lock and total
+// options are ignored.
+// Supports [Prefix], [Where], [WhereConds], [WithDeleted], [OnlyDeleted]
+// and [With]. With [GroupBy], [Having] or [Distinct] the grouped or distinct
+// select is counted as a subquery, honouring [Fields], [Exclude] and [Agg].
+//
+//	sql, args, _ := m.Count(norm.Where("active = ?", true))
+//	// "SELECT count(*) FROM users WHERE active = $1"
+//	var n int64
+//	err := pool.QueryRow(ctx, sql, args...).Scan(&n)
+//
+//	sql, _, _ = m.Count(norm.GroupBy("Status"))
+//	// "SELECT count(*) FROM (SELECT status FROM users GROUP BY status) sub"
+func (m *Model) Count(opts ...Option) (string, []any, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	ff, co, err := m.filteredFields("Count", opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	b := m.newBinder()
+	with, err := m.config.withSQL(b, co.With)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(co.GroupBy) == 0 && co.Having == nil && !co.Distinct {
+		where, err := m.selectWhere(b, co, nil, nil)
+		if err != nil {
+			return "", nil, err
+		}
+		return with + "SELECT count(*) FROM " + m.ident(m.table) + where, b.args, nil
+	}
+
+	co.With, co.OrderBy, co.Limit, co.Offset = nil, "", 0, 0
+	co.Keyset, co.Total, co.Lock = nil, nil, Lock{}
+	sql, args, err := m.selectSQL(ff, co)
+	if err != nil {
+		return "", nil, err
+	}
+	sub := b.embed(Query{SQL: sql, Args: args, dialect: m.config.Dialect})
+
+	return with + "SELECT count(*) FROM (" + sub + ") sub", b.args, nil
 }
 
 // Insert builds a full INSERT query and returns the SQL string and values
 // from the bound struct. Struct fields are automatically JSON-marshaled.
-// Supports [Exclude], [Fields], and [Returning] options.
+// Columns tagged autoCreateTime or autoUpdateTime are set to the current
+// time (see [Config.NowFunc]).
+// Supports [Exclude], [Fields], [Returning], and [OnConflict] options.
 //
 //	sql, vals, _ := m.Insert(norm.Exclude("id"), norm.Returning("Id"))
 //	// "INSERT INTO users (name, email) VALUES ($1, $2) RETURNING id"
 func (m *Model) Insert(opts ...Option) (string, []any, error) {
-	co := ComposeOptions(opts...)
-
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
-	ff, _ := m.filteredFields(opts...)
+	ff, co, err := m.filteredFields("Insert", opts...)
+	if err != nil {
+		return "", nil, err
+	}
 
+	b := m.newBinder()
+	with, err := m.config.withSQL(b, co.With)
+	if err != nil {
+		return "", nil, err
+	}
 	cols := make([]string, 0, len(ff))
 	binds := make([]string, 0, len(ff))
-	vals := make([]any, 0, len(ff))
 
-	for i, f := range ff {
-		cols = append(cols, f.dbName)
-		binds = append(binds, fmt.Sprintf("$%d", i+1))
-		val := m.val.FieldByName(f.name).Interface()
-		if f.IsJSON() {
-			b, err := m.config.JSONMarshal(val)
-			if err != nil {
-				return "", nil, fmt.Errorf("Insert: json marshal field %q: %w", f.name, err)
-			}
-			vals = append(vals, b)
-		} else {
-			vals = append(vals, val)
+	for _, f := range ff {
+		cols = append(cols, m.ident(f.dbName))
+		if f.isAutoTime() {
+			binds = append(binds, m.nowSQL(b))
+			continue
 		}
+		val, err := m.fieldValue(m.val, f)
+		if err != nil {
+			return "", nil, fmt.Errorf("Insert: %w", err)
+		}
+		binds = append(binds, b.bind(val))
 	}
 
-	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		m.table,
+	sql := with + fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		m.ident(m.table),
 		strings.Join(cols, ", "),
 		strings.Join(binds, ", "),
 	)
 
+	conflictSQL, err := m.onConflictSQL(co.OnConflict, ff)
+	if err != nil {
+		return "", nil, err
+	}
+	sql += conflictSQL
+
+	retSQL, err := m.returningSQL(co.Returning)
+	if err != nil {
+		return "", nil, err
+	}
+	sql += retSQL
+
+	return sql, b.args, nil
+}
+
+// InsertSelect builds an INSERT ... SELECT query that inserts the rows
+// returned by q instead of the bound struct. q must return one value for
+// each inserted column, in the order of the struct fields; auto-time
+// columns are not filled in. Supports [Exclude], [Fields], [With],
+// [OnConflict] and [Returning]. Returns an error if q failed to build or,
+// for a q built with [Model.SubSelect], returns a different number of
+// columns.
+//
+// Together with [With] it moves rows between tables in one statement:
+//
+//	moved := norm.NewQuery(mTask.Delete(norm.Where("done_at < ?", cutoff), norm.Returning("*")))
+//	sql, args, _ := mArchive.InsertSelect(
+//	    norm.Query{SQL: "SELECT id, title, done_at FROM moved"},
+//	    norm.With("moved", moved),
+//	)
+//	// "WITH moved AS (DELETE FROM tasks WHERE done_at < $1 RETURNING *)
+//	//  INSERT INTO task_archive (id, title, done_at) SELECT id, title, done_at FROM moved"
+func (m *Model) InsertSelect(q Query, opts ...Option) (string, []any, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	if q.err != nil {
+		return "", nil, fmt.Errorf("InsertSelect: %w", q.err)
+	}
+
+	ff, co, err := m.filteredFields("InsertSelect", opts...)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(ff) == 0 {
+		return "", nil, errors.New("InsertSelect: no fields to insert")
+	}
+	if q.cols != 0 && q.cols != len(ff) {
+		return "", nil, fmt.Errorf("InsertSelect: query returns %d columns, inserting %d", q.cols, len(ff))
+	}
+
+	b := m.newBinder()
+	with, err := m.config.withSQL(b, co.With)
+	if err != nil {
+		return "", nil, err
+	}
+
+	cols := make([]string, len(ff))
+	for i, f := range ff {
+		cols[i] = m.ident(f.dbName)
+	}
+	sql := with + fmt.Sprintf("INSERT INTO %s (%s) %s",
+		m.ident(m.table),
+		strings.Join(cols, ", "),
+		b.embed(q),
+	)
+
+	conflictSQL, err := m.onConflictSQL(co.OnConflict, ff)
+	if err != nil {
+		return "", nil, err
+	}
+	sql += conflictSQL
+
 	retSQL, err := m.returningSQL(co.Returning)
 	if err != nil {
 		return "", nil, err
 	}
 	sql += retSQL
 
-	return sql, vals, nil
+	return sql, b.args, nil
+}
+
+// InsertMany builds multi-row INSERT queries for a slice of structs (or
+// pointers to structs) of the model's type. The rows are split into as
+// many statements as needed to stay within the bind parameter limit of the
+// dialect, see [Dialect.MaxBinds]. Struct fields are automatically
+// JSON-marshaled.
+// Supports [Exclude], [Fields], [Returning], and [OnConflict] options.
+//
+// InsertMany does not use the bound struct — only its type. An empty
+// slice produces no queries.
+//
+//	users := []User{{Name: "Alice"}, {Name: "Bob"}}
+//	queries, _ := m.InsertMany(users, norm.Exclude("id"))
+//	// "INSERT INTO users (name, email) VALUES ($1, $2), ($3, $4)"
+//	for _, q := range queries {
+//	    _, err := pool.Exec(ctx, q.SQL, q.Args...)
+//	}
+func (m *Model) InsertMany(rows any, opts ...Option) ([]Query, error) {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("InsertMany: rows must be a slice, got %T", rows)
+	}
+
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	elemType := rv.Type().Elem()
+	if elemType != m.valType && !(elemType.Kind() == reflect.Pointer && elemType.Elem() == m.valType) {
+		return nil, fmt.Errorf("InsertMany: rows must be a slice of %s, got %T", m.valType, rows)
+	}
+
+	ff, co, err := m.filteredFields("InsertMany", opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(ff) == 0 {
+		return nil, errors.New("InsertMany: no fields to insert")
+	}
+
+	conflictSQL, err := m.onConflictSQL(co.OnConflict, ff)
+	if err != nil {
+		return nil, err
+	}
+
+	retSQL, err := m.returningSQL(co.Returning)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]string, 0, len(ff))
+	for _, f := range ff {
+		cols = append(cols, m.ident(f.dbName))
+	}
+	head := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", m.ident(m.table), strings.Join(cols, ", "))
+
+	count := rv.Len()
+	perStmt := max(m.config.Dialect.MaxBinds()/len(ff), 1)
+	queries := make([]Query, 0, (count+perStmt-1)/perStmt)
+
+	for start := 0; start < count; start += perStmt {
+		end := min(start+perStmt, count)
+
+		b := m.newBinder()
+		tuples := make([]string, 0, end-start)
+		binds := make([]string, len(ff))
+
+		for i := start; i < end; i++ {
+			row := reflect.Indirect(rv.Index(i))
+			if !row.IsValid() {
+				return nil, fmt.Errorf("InsertMany: row %d is nil", i)
+			}
+			for j, f := range ff {
+				if f.isAutoTime() {
+					binds[j] = m.nowSQL(b)
+					continue
+				}
+				val, err := m.fieldValue(row, f)
+				if err != nil {
+					return nil, fmt.Errorf("InsertMany: row %d: %w", i, err)
+				}
+				binds[j] = b.bind(val)
+			}
+			tuples = append(tuples, "("+strings.Join(binds, ", ")+")")
+		}
+
+		queries = append(queries, Query{
+			SQL:  head + strings.Join(tuples, ", ") + conflictSQL + retSQL,
+			Args: b.args,
+		})
+	}
+
+	return queries, nil
+}
+
+// fieldValue returns the value of field f in the struct value row, ready
+// to be passed as a bind argument. Struct fields are JSON-marshaled.
+func (m *modelMeta) fieldValue(row reflect.Value, f *Field) (any, error) {
+	val := row.FieldByName(f.name).Interface()
+	if f.IsJSON() {
+		b, err := m.config.JSONMarshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("json marshal field %q: %w", f.name, err)
+		}
+		return b, nil
+	}
+	return val, nil
 }
 
 // Update builds a full UPDATE query and returns the SQL string and combined
 // args (SET values followed by WHERE args). Struct fields are automatically
 // JSON-marshaled. Bind numbering is chained: SET uses $1..$N, WHERE
-// continues from $N+1.
+// continues from $N+1. Columns tagged autoUpdateTime are set to the current
+// time; autoCreateTime columns are skipped unless listed in [Fields].
+// For models with a version field the update is guarded by optimistic
+// locking, see [ErrVersionConflict].
 // Supports [Exclude], [Fields], [Where], and [Returning] options.
 //
 //	sql, args, _ := m.Update(norm.Exclude("id"), norm.Where("id = ?", user.Id))
 //	// "UPDATE users SET name=$1, email=$2 WHERE id=$3"
 func (m *Model) Update(opts ...Option) (string, []any, error) {
-	co := ComposeOptions(opts...)
-
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
-	ff, _ := m.filteredFields(opts...)
+	ff, co, err := m.filteredFields("Update", opts...)
+	if err != nil {
+		return "", nil, err
+	}
 
+	return m.updateSQL("Update", ff, co)
+}
+
+// updateSQL builds an UPDATE query that sets the given fields from the bound
+// struct. method is used as the error prefix. preds are extra WHERE
+// predicates combined with the [Where] option.
+// Must be called under m.mut.RLock.
+func (m *Model) updateSQL(method string, ff []*Field, co ComposedOptions, preds ...*whereOption) (string, []any, error) {
+	ff = m.setFields(ff, co)
 	if len(ff) == 0 {
-		return "", nil, errors.New("Update: no fields to set")
+		return "", nil, fmt.Errorf("%s: no fields to set", method)
 	}
 
+	b := m.newBinder()
+	with, err := m.config.withSQL(b, co.With)
+	if err != nil {
+		return "", nil, err
+	}
 	setCols := make([]string, 0, len(ff))
-	vals := make([]any, 0, len(ff))
 
-	for i, f := range ff {
-		setCols = append(setCols, fmt.Sprintf("%s=$%d", f.dbName, i+1))
-		val := m.val.FieldByName(f.name).Interface()
-		if f.IsJSON() {
-			b, err := m.config.JSONMarshal(val)
-			if err != nil {
-				return "", nil, fmt.Errorf("Update: json marshal field %q: %w", f.name, err)
-			}
-			vals = append(vals, b)
-		} else {
-			vals = append(vals, val)
+	for _, f := range ff {
+		col := m.ident(f.dbName)
+		switch {
+		case f.dbName == m.version:
+			setCols = append(setCols, col+"="+col+"+1")
+			continue
+		case f.hasTag("autoUpdateTime"):
+			setCols = append(setCols, col+"="+m.nowSQL(b))
+			continue
 		}
+		val, err := m.fieldValue(m.val, f)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: %w", method, err)
+		}
+		setCols = append(setCols, col+"="+b.bind(val))
 	}
 
-	sql := fmt.Sprintf("UPDATE %s SET %s", m.table, strings.Join(setCols, ", "))
+	sql := with + fmt.Sprintf("UPDATE %s SET %s", m.ident(m.table), strings.Join(setCols, ", "))
 
-	if co.Where != nil {
-		whereStr, _ := co.Where.Build(len(ff) + 1)
-		sql += " WHERE " + whereStr
-		vals = append(vals, co.Where.Args...)
+	versionPred, err := m.versionWhere(method)
+	if err != nil {
+		return "", nil, err
 	}
+	where, err := renderWhere(b, append(preds, m.structWhere(co.Where), m.whereConds(method, co, ""), versionPred)...)
+	if err != nil {
+		return "", nil, err
+	}
+	sql += where
 
-	retSQL, err := m.returningSQL(co.Returning)
+	retSQL, err := m.returningSQL(m.withVersion(co.Returning))
 	if err != nil {
 		return "", nil, err
 	}
 	sql += retSQL
 
-	return sql, vals, nil
+	return sql, b.args, nil
+}
+
+// setFields adjusts the SET fields of an UPDATE: the version column and
+// autoUpdateTime columns are always added (the latter unless excluded),
+// autoCreateTime columns are dropped unless listed in [Fields].
+// Field order follows the struct.
+func (m *modelMeta) setFields(ff []*Field, co ComposedOptions) []*Field {
+	res := make([]*Field, 0, len(ff)+2)
+	for _, f := range m.fields {
+		switch {
+		case f.dbName == m.version:
+			res = append(res, f)
+		case f.hasTag("autoUpdateTime"):
+			if !has(co.Exclude, f.dbName) && !has(m.pk, f.dbName) {
+				res = append(res, f)
+			}
+		case f.hasTag("autoCreateTime") && !has(co.Fields, f.dbName):
+		case containsField(ff, f):
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// nowSQL returns the SQL for the current time: a bind of [Config.NowFunc]
+// if set, otherwise the current time function of the dialect. NowFunc is
+// called once per statement, so every auto-time column of every row gets
+// the same value.
+func (m *modelMeta) nowSQL(b *binder) string {
+	if m.config.NowFunc == nil {
+		return m.config.Dialect.Now()
+	}
+	if b.now == nil {
+		b.now = m.config.NowFunc()
+	}
+	return b.bind(b.now)
 }
 
 // Delete builds a full DELETE query and returns the SQL string and WHERE args.
-// Supports [Where] and [Returning] options.
+// Supports [Where], [Returning], and [HardDelete] options.
+//
+// For models with a softdelete field, Delete renders an UPDATE that sets
+// the column to the current time instead, skipping rows that are already deleted.
+// Pass [HardDelete] to remove rows physically.
 //
 //	sql, args, _ := m.Delete(norm.Where("id = ?", 42))
 //	// "DELETE FROM users WHERE id=$1"
+//	// soft delete: "UPDATE users SET deleted_at=now() WHERE (id = $1) AND deleted_at IS NULL"
 func (m *Model) Delete(opts ...Option) (string, []any, error) {
 	co := ComposeOptions(opts...)
 
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
-	sql := fmt.Sprintf("DELETE FROM %s", m.table)
+	return m.deleteSQL(co)
+}
+
+// deleteSQL builds a DELETE query, or a soft-delete UPDATE for models with
+// a softdelete field. preds are extra WHERE predicates combined with the
+// [Where] option.
+// Must be called under m.mut.RLock.
+func (m *Model) deleteSQL(co ComposedOptions, preds ...*whereOption) (string, []any, error) {
+	var scope *whereOption
+
+	b := m.newBinder()
+	sql, err := m.config.withSQL(b, co.With)
+	if err != nil {
+		return "", nil, err
+	}
 
-	var args []any
+	if m.softDelete != "" && !co.HardDelete {
+		sql += fmt.Sprintf("UPDATE %s SET %s=%s", m.ident(m.table), m.ident(m.softDelete), m.nowSQL(b))
+		scope = m.softDeleteWhere("", co.Deleted)
+	} else {
+		sql += fmt.Sprintf("DELETE FROM %s", m.ident(m.table))
+		if co.Deleted == onlyDeleted {
+			scope = m.softDeleteWhere("", co.Deleted)
+		}
+	}
 
-	if co.Where != nil {
-		whereStr, _ := co.Where.Build(1)
-		sql += " WHERE " + whereStr
-		args = append(args, co.Where.Args...)
+	where, err := renderWhere(b, append(preds, m.structWhere(co.Where), m.whereConds("Delete", co, ""), scope)...)
+	if err != nil {
+		return "", nil, err
 	}
+	sql += where
 
 	retSQL, err := m.returningSQL(co.Returning)
 	if err != nil {
@@ -437,32 +1000,166 @@ func (m *Model) Delete(opts ...Option) (string, []any, error) {
 	}
 	sql += retSQL
 
-	return sql, args, nil
+	return sql, b.args, nil
 }
 
-// returningSQL builds a RETURNING clause from field names.
+// softDeleteWhere returns the soft-delete filter for the given scope, or nil
+// if the model has no softdelete field or the scope includes deleted rows.
+func (m *modelMeta) softDeleteWhere(prefix string, scope deletedScope) *whereOption {
+	if m.softDelete == "" {
+		return nil
+	}
+	switch scope {
+	case withDeleted:
+		return nil
+	case onlyDeleted:
+		return &whereOption{template: prefix + m.ident(m.softDelete) + " IS NOT NULL", grouped: true}
+	default:
+		return &whereOption{template: prefix + m.ident(m.softDelete) + " IS NULL", grouped: true}
+	}
+}
+
+// returningSQL builds a RETURNING clause from field names; "*" returns
+// all columns.
 // Must be called under m.mut.RLock.
 func (m *modelMeta) returningSQL(returning []string) (string, error) {
 	if len(returning) == 0 {
 		return "", nil
 	}
+	if err := unsupported("Returning", m.config.Dialect, FeatureReturning); err != nil {
+		return "", err
+	}
 	ret := make([]string, 0, len(returning))
 	for _, name := range returning {
 		name = strings.TrimSpace(name)
+		if name == "*" {
+			ret = append(ret, "*")
+			continue
+		}
 		field, ok := m.fieldByAnyName[name]
 		if !ok {
 			return "", fmt.Errorf("Returning: unknown field %q", name)
 		}
-		ret = append(ret, field.dbName)
+		ret = append(ret, m.ident(field.dbName))
+	}
+	sql, err := m.config.Dialect.Returning(ret)
+	if err != nil {
+		return "", fmt.Errorf("Returning: %w", err)
 	}
-	return " RETURNING " + strings.Join(ret, ", "), nil
+	return sql, nil
+}
+
+// onConflictSQL builds an ON CONFLICT clause for an INSERT of the given
+// fields. Returns empty string if oc is nil.
+// Must be called under m.mut.RLock.
+func (m *modelMeta) onConflictSQL(oc *onConflictOption, inserted []*Field) (string, error) {
+	if oc == nil {
+		return "", nil
+	}
+
+	var target []string
+	var targetSQL string
+
+	switch {
+	case oc.noTarget:
+	case oc.constraint != "":
+		if !isValidIdentifier(oc.constraint) {
+			return "", fmt.Errorf("OnConflict: invalid constraint name %q", oc.constraint)
+		}
+		targetSQL = "ON CONSTRAINT " + m.ident(oc.constraint)
+	case len(oc.fields) > 0:
+		for _, name := range oc.fields {
+			name = strings.TrimSpace(name)
+			field, ok := m.fieldByAnyName[name]
+			if !ok {
+				return "", fmt.Errorf("OnConflict: unknown field %q", name)
+			}
+			target = append(target, field.dbName)
+		}
+		targetSQL = m.identList(target)
+	case len(m.pk) > 0:
+		target = m.pk
+		targetSQL = m.identList(target)
+	case oc.doUpdate:
+		return "", fmt.Errorf("OnConflict: model %q has no pk fields, specify the conflict target", m.table)
+	}
+
+	cols := make([]string, len(inserted))
+	for i, f := range inserted {
+		cols[i] = m.ident(f.dbName)
+	}
+
+	var set []string
+	if oc.doUpdate {
+		co := ComposeOptions(oc.update...)
+		if err := m.resolveFieldOptions("OnConflict", &co); err != nil {
+			return "", err
+		}
+
+		for _, f := range filterFields(inserted, co) {
+			if has(target, f.dbName) || f.hasTag("autoCreateTime") {
+				continue
+			}
+			col := m.ident(f.dbName)
+			if f.dbName == m.version {
+				set = append(set, fmt.Sprintf("%s=%s.%s+1", col, m.ident(m.table), col))
+				continue
+			}
+			set = append(set, col+"="+m.config.Dialect.Excluded(col))
+		}
+		if len(set) == 0 {
+			return "", errors.New("OnConflict: no fields to update")
+		}
+	}
+
+	sql, err := m.config.Dialect.Upsert(targetSQL, cols, set)
+	if err != nil {
+		return "", fmt.Errorf("OnConflict: %w", err)
+	}
+	return sql, nil
 }
 
 // orderBySQL validates and renders an ORDER BY clause.
 // Must be called under m.mut.RLock.
 func (m *modelMeta) orderBySQL(orderBy string) string {
+	terms := m.parseOrderBy(orderBy)
+
+	res := make([]string, 0, len(terms))
+	for _, t := range terms {
+		res = append(res, t.sql(""))
+	}
+
+	return strings.Join(res, ", ")
+}
+
+// orderTerm is a single validated ORDER BY column: a model field or the
+// alias of a select item.
+type orderTerm struct {
+	field *Field
+	col   string // field column or alias as rendered in SQL
+	alias string
+	desc  bool
+}
+
+// sql renders the term with the given column prefix. Aliases are never
+// prefixed.
+func (t orderTerm) sql(prefix string) string {
+	col := t.col
+	if t.field != nil {
+		col = prefix + t.col
+	}
+	if t.desc {
+		return col + " DESC"
+	}
+	return col + " ASC"
+}
+
+// parseOrderBy validates an ORDER BY clause against the model and the
+// given select item aliases. Panics on unknown fields or invalid directions.
+// Must be called under m.mut.RLock.
+func (m *modelMeta) parseOrderBy(orderBy string, aliases ...string) []orderTerm {
 	parts := strings.Split(orderBy, ",")
-	res := make([]string, 0, len(parts))
+	res := make([]orderTerm, 0, len(parts))
 
 	for _, part := range parts {
 		tokens := strings.Fields(strings.TrimSpace(part))
@@ -483,15 +1180,20 @@ func (m *modelMeta) orderBySQL(orderBy string) string {
 			panic(fmt.Sprintf("OrderBy: invalid direction %q, must be ASC or DESC", direction))
 		}
 
+		if has(aliases, fieldName) {
+			res = append(res, orderTerm{alias: fieldName, col: m.ident(fieldName), desc: direction == "DESC"})
+			continue
+		}
+
 		field, ok := m.fieldByAnyName[fieldName]
 		if !ok {
 			panic(fmt.Sprintf("OrderBy: unknown field %q", fieldName))
 		}
 
-		res = append(res, field.dbName+" "+direction)
+		res = append(res, orderTerm{field: field, col: m.ident(field.dbName), desc: direction == "DESC"})
 	}
 
-	return strings.Join(res, ", ")
+	return res
 }
 
 // NewInstance creates and returns a new zero-value pointer to the struct type
@@ -519,7 +1221,7 @@ func (m *modelMeta) FieldByName(name string) (*Field, bool) {
 
 // Returning validates field names and returns a RETURNING clause string
 // (e.g. "RETURNING id, name"). Fields is a comma-separated list of field
-// names in any format (struct name, camelCase, or db name).
+// names in any format (struct name, camelCase, or db name), or "*".
 // Returns empty string if fields is empty.
 // Panics if a field is not found — this is a programmer error.
 //
@@ -541,11 +1243,15 @@ func (m *modelMeta) Returning(fields string) string {
 		if name == "" {
 			continue
 		}
+		if name == "*" {
+			res = append(res, "*")
+			continue
+		}
 		field, ok := m.fieldByAnyName[name]
 		if !ok {
 			panic(fmt.Sprintf("Returning: unknown field %q", name))
 		}
-		res = append(res, field.dbName)
+		res = append(res, m.ident(field.dbName))
 	}
 
 	if len(res) == 0 {
@@ -560,15 +1266,7 @@ func (m *modelMeta) Returning(fields string) string {
 //	m.LimitOffset(10, 0)   // "LIMIT 10"
 //	m.LimitOffset(10, 20)  // "LIMIT 10 OFFSET 20"
 func (m *modelMeta) LimitOffset(limit, offset int) string {
-	var parts []string
-	if limit > 0 {
-		parts = append(parts, fmt.Sprintf("LIMIT %d", limit))
-	}
-	if offset > 0 {
-		parts = append(parts, fmt.Sprintf("OFFSET %d", offset))
-	}
-
-	return strings.Join(parts, " ")
+	return m.config.Dialect.LimitOffset(limit, offset)
 }
 
 // FieldDescriptions returns the slice of all [Field] descriptors for this model.
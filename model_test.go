@@ -583,6 +583,232 @@ func TestInsert(t *testing.T) {
 	})
 }
 
+func TestInsertMany(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&ModelTestStruct{})
+
+	t.Run("multiple rows", func(t *testing.T) {
+		rows := []ModelTestStruct{
+			{Name: "Alice", Email: "alice@test.com", Age: 25},
+			{Name: "Bob", Email: "bob@test.com", Age: 30},
+		}
+		queries, err := m.InsertMany(rows, Exclude("id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(queries) != 1 {
+			t.Fatalf("expected 1 query, got %d", len(queries))
+		}
+		want := "INSERT INTO model_test_struct (name, email, age) VALUES ($1, $2, $3), ($4, $5, $6)"
+		if queries[0].SQL != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", queries[0].SQL, want)
+		}
+		args := queries[0].Args
+		if len(args) != 6 || args[0] != "Alice" || args[3] != "Bob" || args[5] != 30 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("slice of pointers with returning", func(t *testing.T) {
+		rows := []*ModelTestStruct{{Name: "Alice"}, {Name: "Bob"}}
+		queries, err := m.InsertMany(rows, Fields("name"), Returning("Id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO model_test_struct (name) VALUES ($1), ($2) RETURNING id"
+		if len(queries) != 1 || queries[0].SQL != want {
+			t.Errorf("unexpected queries: %+v", queries)
+		}
+	})
+
+	t.Run("splits on bind parameter limit", func(t *testing.T) {
+		rows := make([]ModelTestStruct, Postgres.MaxBinds()/3+1)
+		queries, err := m.InsertMany(rows, Exclude("id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(queries) != 2 {
+			t.Fatalf("expected 2 queries, got %d", len(queries))
+		}
+		if len(queries[0].Args) != Postgres.MaxBinds()/3*3 {
+			t.Errorf("expected %d args in first query, got %d", Postgres.MaxBinds()/3*3, len(queries[0].Args))
+		}
+		want := "INSERT INTO model_test_struct (name, email, age) VALUES ($1, $2, $3)"
+		if queries[1].SQL != want {
+			t.Errorf("got %q", queries[1].SQL)
+		}
+	})
+
+	t.Run("splits on the sqlite limit", func(t *testing.T) {
+		ms, _ := NewNorm(&Config{Dialect: SQLite}).M(&ModelTestStruct{})
+		rows := make([]ModelTestStruct, 32766/3+1)
+		queries, err := ms.InsertMany(rows, Exclude("id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(queries) != 2 || len(queries[0].Args) != 32766/3*3 || len(queries[1].Args) != 3 {
+			t.Errorf("unexpected split: %d queries", len(queries))
+		}
+	})
+
+	t.Run("json fields marshaled", func(t *testing.T) {
+		mj, _ := n.M(&JSONUser{})
+		rows := []JSONUser{{Name: "Alice", Address: JSONAddress{City: "Moscow"}}}
+		queries, err := mj.InsertMany(rows, Exclude("id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, ok := queries[0].Args[1].([]byte)
+		if !ok || string(b) != `{"city":"Moscow","street":""}` {
+			t.Errorf("unexpected json arg: %v", queries[0].Args[1])
+		}
+	})
+
+	t.Run("empty slice", func(t *testing.T) {
+		queries, err := m.InsertMany([]ModelTestStruct{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(queries) != 0 {
+			t.Errorf("expected no queries, got %d", len(queries))
+		}
+	})
+
+	t.Run("wrong element type errors", func(t *testing.T) {
+		_, err := m.InsertMany([]JSONUser{{}})
+		if err == nil {
+			t.Error("expected error for wrong element type")
+		}
+	})
+
+	t.Run("not a slice errors", func(t *testing.T) {
+		_, err := m.InsertMany(ModelTestStruct{})
+		if err == nil {
+			t.Error("expected error for non-slice")
+		}
+	})
+
+	t.Run("nil row errors", func(t *testing.T) {
+		_, err := m.InsertMany([]*ModelTestStruct{nil})
+		if err == nil {
+			t.Error("expected error for nil row")
+		}
+	})
+}
+
+type NoPKStruct struct {
+	Name  string
+	Email string
+}
+
+func TestInsertOnConflict(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&ModelTestStruct{Name: "Alice", Email: "alice@test.com", Age: 25})
+
+	t.Run("do update on field", func(t *testing.T) {
+		sql, vals, err := m.Insert(Exclude("id"), OnConflict("Email").DoUpdate())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO model_test_struct (name, email, age) VALUES ($1, $2, $3) ON CONFLICT (email) DO UPDATE SET name=EXCLUDED.name, age=EXCLUDED.age"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(vals) != 3 {
+			t.Errorf("expected 3 vals, got %d", len(vals))
+		}
+	})
+
+	t.Run("do update defaults to pk", func(t *testing.T) {
+		sql, _, err := m.Insert(OnConflict().DoUpdate(Exclude("age")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO model_test_struct (id, name, email, age) VALUES ($1, $2, $3, $4) ON CONFLICT (id) DO UPDATE SET name=EXCLUDED.name, email=EXCLUDED.email"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+
+	t.Run("do update with fields and returning", func(t *testing.T) {
+		sql, _, err := m.Insert(
+			Exclude("id"),
+			OnConflict("email").DoUpdate(Fields("name")),
+			Returning("Id"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO model_test_struct (name, email, age) VALUES ($1, $2, $3) ON CONFLICT (email) DO UPDATE SET name=EXCLUDED.name RETURNING id"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+
+	t.Run("on constraint do nothing", func(t *testing.T) {
+		sql, _, err := m.Insert(Exclude("id"), OnConstraint("users_email_key").DoNothing())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO model_test_struct (name, email, age) VALUES ($1, $2, $3) ON CONFLICT ON CONSTRAINT users_email_key DO NOTHING"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("do nothing without target", func(t *testing.T) {
+		sql, _, err := m.Insert(Exclude("id"), OnConflictDoNothing())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO model_test_struct (name, email, age) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("insert many", func(t *testing.T) {
+		rows := []ModelTestStruct{{Name: "Alice"}, {Name: "Bob"}}
+		queries, err := m.InsertMany(rows, Fields("name,email"), OnConflict("Email").DoUpdate())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO model_test_struct (name, email) VALUES ($1, $2), ($3, $4) ON CONFLICT (email) DO UPDATE SET name=EXCLUDED.name"
+		if queries[0].SQL != want {
+			t.Errorf("got %q", queries[0].SQL)
+		}
+	})
+
+	t.Run("unknown target field errors", func(t *testing.T) {
+		_, _, err := m.Insert(OnConflict("nonexistent").DoNothing())
+		if err == nil {
+			t.Error("expected error for unknown conflict field")
+		}
+	})
+
+	t.Run("invalid constraint name errors", func(t *testing.T) {
+		_, _, err := m.Insert(OnConstraint("bad name").DoNothing())
+		if err == nil {
+			t.Error("expected error for invalid constraint name")
+		}
+	})
+
+	t.Run("nothing to update errors", func(t *testing.T) {
+		_, _, err := m.Insert(Fields("email"), OnConflict("Email").DoUpdate())
+		if err == nil {
+			t.Error("expected error when no fields are left to update")
+		}
+	})
+
+	t.Run("no pk target errors", func(t *testing.T) {
+		mn, _ := n.M(&NoPKStruct{})
+		_, _, err := mn.Insert(OnConflict().DoUpdate())
+		if err == nil {
+			t.Error("expected error for model without pk")
+		}
+	})
+}
+
 func TestUpdate(t *testing.T) {
 	n := NewNorm(nil)
 	user := &ModelTestStruct{Id: 1, Name: "Bob", Email: "bob@test.com", Age: 30}
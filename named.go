@@ -0,0 +1,151 @@
+package norm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WhereNamed creates an option that adds a WHERE clause with ":name"
+// placeholders. Values are taken from params; every occurrence of the same
+// name reuses a single bind, or binds the value again in dialects with "?"
+// placeholders. Slice values are expanded like in [Where].
+// PostgreSQL casts ("::int"), "?" and anything
+// inside string literals or comments are left as is.
+// A placeholder without a value in params makes the builder return an error.
+//
+//	m.Select(norm.WhereNamed(
+//	    "created_at >= :from AND (updated_at >= :from OR owner = :owner)",
+//	    map[string]any{"from": since, "owner": "alice"},
+//	))
+//	// "... WHERE created_at >= $1 AND (updated_at >= $1 OR owner = $2)"
+func WhereNamed(where string, params map[string]any) Option {
+	return namedWhere(where, params)
+}
+
+// WhereStruct creates an option that adds a WHERE clause with ":Name"
+// placeholders bound to fields of the model's bound struct. Names are
+// resolved in any format (Go name, camelCase, snake_case), so ":TenantId",
+// ":tenantId" and ":tenant_id" are the same field. Unknown names make the
+// builder return an error wrapping [ErrUnknownField].
+//
+//	user := User{TenantId: 7, Email: "a@b.c"}
+//	m, _ := orm.M(&user)
+//	sql, args, _ := m.Select(norm.WhereStruct("tenant_id = :TenantId AND email = :Email"))
+//	// "SELECT ... WHERE tenant_id = $1 AND email = $2", args = [7, "a@b.c"]
+func WhereStruct(where string) Option {
+	w := namedWhere(where, nil)
+	if w != nil {
+		w.lookup = nil // bound to the struct by the model at build time
+	}
+	return w
+}
+
+// namedWhere creates a whereOption with ":name" placeholders taking values
+// from params. Returns nil if where is empty.
+func namedWhere(where string, params map[string]any) *whereOption {
+	if where == "" {
+		return nil
+	}
+	return &whereOption{
+		template: where,
+		named:    true,
+		lookup: func(name string) (any, error) {
+			v, ok := params[name]
+			if !ok {
+				return nil, fmt.Errorf("WhereNamed: missing value for :%s", name)
+			}
+			return v, nil
+		},
+	}
+}
+
+// renderNamed writes a template with ":name" placeholders, binding each
+// distinct name once.
+func (w *whereOption) renderNamed(b *binder) (string, error) {
+	if w.lookup == nil {
+		return "", errors.New("WhereStruct: not supported without a bound model")
+	}
+
+	binds := make(map[string]string)
+	values := make(map[string]any)
+	t := w.template
+
+	var sb strings.Builder
+	sb.Grow(len(t) + 8)
+	for i := 0; i < len(t); i++ {
+		if end := skipQuoted(t, i); end > i {
+			sb.WriteString(t[i:end])
+			i = end - 1
+			continue
+		}
+		c := t[i]
+		if c != ':' {
+			sb.WriteByte(c)
+			continue
+		}
+		// PostgreSQL cast: value::type
+		if i+1 < len(t) && t[i+1] == ':' {
+			sb.WriteString("::")
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(t) && isNameByte(t[end], end == i+1) {
+			end++
+		}
+		if end == i+1 {
+			sb.WriteByte(c)
+			continue
+		}
+
+		name := t[i+1 : end]
+		ph, ok := binds[name]
+		switch {
+		case !ok:
+			v, err := w.lookup(name)
+			if err != nil {
+				return "", err
+			}
+			if ph, err = b.bindList(v, sb.String()); err != nil {
+				return "", err
+			}
+			binds[name] = ph
+			values[name] = v
+		case b.positional || ph == "NULL":
+			// bind again, or check an empty list against its new context
+			var err error
+			if ph, err = b.bindList(values[name], sb.String()); err != nil {
+				return "", err
+			}
+		}
+		sb.WriteString(ph)
+		i = end - 1
+	}
+
+	return sb.String(), nil
+}
+
+// structWhere binds a [WhereStruct] predicate to the fields of the bound
+// struct. Other predicates are returned unchanged.
+// Must be called under m.mut.RLock.
+func (m *Model) structWhere(w *whereOption) *whereOption {
+	if w == nil || !w.named || w.lookup != nil {
+		return w
+	}
+
+	bound := *w
+	bound.lookup = func(name string) (any, error) {
+		f, ok := m.fieldByAnyName[name]
+		if !ok {
+			return nil, m.unknownFieldError("WhereStruct", name)
+		}
+		v, err := m.fieldValue(m.val, f)
+		if err != nil {
+			return nil, fmt.Errorf("WhereStruct: %w", err)
+		}
+		return v, nil
+	}
+	return &bound
+}
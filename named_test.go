@@ -0,0 +1,120 @@
+package norm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWhereNamed(t *testing.T) {
+	m := newTestModel()
+
+	t.Run("repeated names reuse bind", func(t *testing.T) {
+		sql, args, err := m.Select(Fields("id"), WhereNamed(
+			"age > :min AND (name = :name OR email = :name) AND age < :min + 10",
+			map[string]any{"min": 18, "name": "Alice"},
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE age > $1 AND (name = $2 OR email = $2) AND age < $1 + 10"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 2 || args[0] != 18 || args[1] != "Alice" {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("casts untouched", func(t *testing.T) {
+		sql, args, err := m.Select(Fields("id"), WhereNamed("age::text = :age_str", map[string]any{"age_str": "5"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE age::text = $1"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 1 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("update binds continue after set", func(t *testing.T) {
+		sql, args, err := m.Update(Fields("name"), WhereNamed("id = :id", map[string]any{"id": 7}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "UPDATE model_test_struct SET name=$1 WHERE id = $2"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 2 || args[1] != 7 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		_, _, err := m.Delete(WhereNamed("id = :id", nil))
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("join", func(t *testing.T) {
+		mUser, mOrder, _ := setupJoinModels(t)
+		sql, args, err := NewJoin(mUser).
+			Inner(mOrder, "join_order.user_id = join_user.id").
+			WhereNamed("join_order.total > :min OR join_order.user_id = :min", map[string]any{"min": 5}).
+			Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT join_user.id, join_user.name, join_user.email, join_order.id, join_order.user_id, join_order.total FROM join_user INNER JOIN join_order ON join_order.user_id = join_user.id WHERE join_order.total > $1 OR join_order.user_id = $1"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 1 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+}
+
+func TestWhereStruct(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&ModelTestStruct{Id: 3, Name: "Bob", Age: 40})
+
+	t.Run("any name format", func(t *testing.T) {
+		sql, args, err := m.Select(Fields("id"), WhereStruct("name = :Name AND age >= :age AND id <> :id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE name = $1 AND age >= $2 AND id <> $3"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 3 || args[0] != "Bob" || args[1] != 40 || args[2] != 3 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("combined with pk", func(t *testing.T) {
+		sql, args, err := m.DeleteByPK(WhereStruct("name = :Name"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "DELETE FROM model_test_struct WHERE id=$1 AND (name = $2)"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 2 || args[1] != "Bob" {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, _, err := m.Select(WhereStruct("name = :Nmae"))
+		if !errors.Is(err, ErrUnknownField) {
+			t.Fatalf("expected ErrUnknownField, got %v", err)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/iancoleman/strcase"
 )
@@ -45,6 +46,58 @@ type Config struct {
 	// JSONUnmarshal is the function used to unmarshal JSON into struct fields.
 	// Defaults to [encoding/json.Unmarshal].
 	JSONUnmarshal func(data []byte, v any) error
+
+	// NowFunc returns the current time for autoCreateTime, autoUpdateTime
+	// and softdelete columns; the value is passed as a bind argument.
+	// If nil, now() is rendered in SQL and the database clock is used.
+	// Set it to make generated args deterministic in tests.
+	//
+	//	orm := norm.NewNorm(&norm.Config{
+	//	    NowFunc: func() time.Time { return fixed },
+	//	})
+	NowFunc func() time.Time
+
+	// Strict makes unknown field names an error instead of silently
+	// ignoring them: in [Exclude] and [Fields] options, in [WhereConds]
+	// and in [Model.BuildConditions]. Methods that cannot return an error
+	// (Fields, Pointers, BuildConditions, ...) panic instead. A typo in a
+	// filter then fails loudly rather than matching every row.
+	//
+	//	orm := norm.NewNorm(&norm.Config{Strict: true})
+	Strict bool
+
+	// SlicesAsArrays passes slice arguments of [Where] and [WhereNamed]
+	// templates as a single array argument instead of expanding them into
+	// one placeholder per element. Write "= ANY(?)" instead of "IN (?)".
+	//
+	//	m.Select(norm.Where("id = ANY(?)", []int{1, 2, 3}))
+	//	// "... WHERE id = ANY($1)", args = [[1 2 3]]
+	SlicesAsArrays bool
+
+	// Dialect selects the SQL flavor of the generated statements:
+	// placeholders, LIMIT/OFFSET, upserts and RETURNING. Defaults to
+	// [Postgres]; [SQLite] and [MySQL] are also provided.
+	//
+	//	orm := norm.NewNorm(&norm.Config{Dialect: norm.SQLite})
+	//	// "SELECT id, name FROM users WHERE id = ?"
+	Dialect Dialect
+
+	// QuoteIdentifiers quotes every table and column name in the generated
+	// SQL. By default only names that need it are quoted: reserved words
+	// such as user or order, names with uppercase letters and names
+	// starting with a digit, see [Norm.QuoteIdent].
+	//
+	//	orm := norm.NewNorm(&norm.Config{QuoteIdentifiers: true})
+	//	// `SELECT "id", "name" FROM "users"`
+	QuoteIdentifiers bool
+
+	// DefaultSchema qualifies the tables of models registered without a
+	// schema: with "app", M(&User{}) renders app."user". Pass a
+	// schema-qualified name to [Norm.AddModel] to use another schema.
+	//
+	//	orm := norm.NewNorm(&norm.Config{DefaultSchema: "app"})
+	//	orm.AddModel(&Invoice{}, "billing.invoices") // billing.invoices
+	DefaultSchema string
 }
 
 var defaultConfig = &Config{}
@@ -72,6 +125,9 @@ func NewNorm(config *Config) *Norm {
 	if config.JSONUnmarshal == nil {
 		config.JSONUnmarshal = json.Unmarshal
 	}
+	if config.Dialect == nil {
+		config.Dialect = Postgres
+	}
 	return &Norm{
 		metas:  make(map[reflect.Type]*modelMeta),
 		tables: make(map[string]*modelMeta),
@@ -83,6 +139,7 @@ func NewNorm(config *Config) *Norm {
 // a [Model] bound to obj. Panics if obj is not a struct or pointer to struct.
 //
 //	orm.AddModel(&User{}, "app_users")
+//	orm.AddModel(&Invoice{}, "billing.invoices")
 func (n *Norm) AddModel(obj any, table string) *Model {
 	meta := newModelMeta(n.config)
 
@@ -95,7 +152,7 @@ func (n *Norm) AddModel(obj any, table string) *Model {
 	defer n.mut.Unlock()
 
 	n.metas[meta.valType] = meta
-	n.tables[table] = meta
+	n.tables[meta.table] = meta
 
 	val := reflect.ValueOf(obj)
 	return &Model{modelMeta: meta, val: val.Elem()}
@@ -138,11 +195,12 @@ func (n *Norm) Tables() []string {
 }
 
 // FieldsByTable returns field descriptors for a registered table.
+// A table without a schema is looked up in [Config.DefaultSchema].
 // Returns nil if the table is not registered.
 func (n *Norm) FieldsByTable(table string) []*Field {
 	n.mut.RLock()
 	defer n.mut.RUnlock()
-	meta, ok := n.tables[table]
+	meta, ok := n.tables[n.config.qualify(table)]
 	if !ok {
 		return nil
 	}
@@ -1,7 +1,6 @@
 package norm
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -9,15 +8,27 @@ import (
 type OptionType int
 
 const (
-	ExcludeOption    OptionType = iota // Exclude fields by db name
-	FieldsOption                       // Include only specified fields
-	ReturningOption                    // RETURNING clause fields
-	PrefixOption                       // Table alias prefix for field names
-	WhereOption                        // WHERE clause with ? placeholders
-	AddTargetsOption                   // Extra scan targets for Pointers
-	OffsetOption                       // OFFSET value
-	LimitOption                        // LIMIT value
-	OrderByOption                      // ORDER BY clause
+	ExcludeOption      OptionType = iota // Exclude fields by db name
+	FieldsOption                         // Include only specified fields
+	ReturningOption                      // RETURNING clause fields
+	PrefixOption                         // Table alias prefix for field names
+	WhereOption                          // WHERE clause with ? placeholders
+	AddTargetsOption                     // Extra scan targets for Pointers
+	OffsetOption                         // OFFSET value
+	LimitOption                          // LIMIT value
+	OrderByOption                        // ORDER BY clause
+	OnConflictOption                     // ON CONFLICT clause for INSERT
+	DeletedScopeOption                   // Soft-deleted rows visibility
+	HardDeleteOption                     // Physical DELETE for soft-delete models
+	WhereCondsOption                     // WHERE clause from typed conditions
+	KeysetOption                         // Keyset pagination cursor
+	TotalOption                          // count(*) OVER() column and scan target
+	GroupByOption                        // GROUP BY clause
+	HavingOption                         // HAVING clause with ? placeholders
+	AggregateOption                      // Aggregate select item
+	LockOption                           // FOR UPDATE / FOR SHARE row locking
+	DistinctOption                       // SELECT DISTINCT / DISTINCT ON
+	CTEOption                            // WITH common table expression
 )
 
 // Option is a functional option for customizing query building methods.
@@ -36,11 +47,39 @@ type (
 	whereOption     struct {
 		template string // original where with "?" placeholders
 		Args     []any
+		grouped  bool                            // safe to join with AND without parentheses
+		build    func(b *binder) (string, error) // renders instead of template when set
+		named    bool                            // template uses :name placeholders
+		lookup   func(name string) (any, error)  // values for :name placeholders
 	}
+	whereCondsOption []Cond
 	addTargetsOption []any
 	offsetOption     int
 	limitOption      int
 	orderByOption    string
+	deletedScope     int
+	hardDeleteOption struct{}
+	onConflictOption struct {
+		fields     []string // conflict target fields, any name format
+		constraint string   // ON CONSTRAINT name, overrides fields
+		noTarget   bool     // plain ON CONFLICT without a target
+		doUpdate   bool
+		update     []Option // Exclude/Fields for the DO UPDATE SET list
+	}
+	keysetOption struct {
+		cursor Cursor
+		before bool
+	}
+	totalOption struct {
+		target any
+	}
+	groupByOption  string
+	distinctOption struct {
+		on string // DISTINCT ON fields, empty for plain DISTINCT
+	}
+	havingOption struct {
+		where *whereOption
+	}
 )
 
 // parseWhere creates a whereOption from a template and args.
@@ -58,24 +97,118 @@ func parseWhere(where string, args ...any) *whereOption {
 
 // Build renders the WHERE clause, replacing each "?" with "$N" starting
 // from startBind. Returns the rendered string and the next bind number.
+// Panics if the clause cannot be rendered, e.g. a [WhereNamed] parameter
+// without a value or a [WhereStruct] clause outside a model. Use
+// BuildDialect for other placeholders.
 func (w *whereOption) Build(startBind int) (string, int) {
-	result := w.template
-	bind := startBind
-	count := strings.Count(result, "?")
-	for i := 0; i < count; i++ {
-		result = strings.Replace(result, "?", fmt.Sprintf("$%d", bind), 1)
-		bind++
+	return w.BuildDialect(Postgres, startBind)
+}
+
+// BuildDialect is like Build with the placeholders of
+// dialect d, usually the [Config.Dialect] of the model the query is for.
+//
+//	where, next := co.Where.BuildDialect(norm.SQLite, 1) // "name = ?"
+func (w *whereOption) BuildDialect(d Dialect, startBind int) (string, int) {
+	b := newDialectBinder(d)
+	b.next = startBind
+	result, err := w.render(b)
+	if err != nil {
+		panic("Build: " + err.Error())
+	}
+	return result, b.next
+}
+
+// render writes the template with placeholders taken from b and appends
+// the args to b.
+func (w *whereOption) render(b *binder) (string, error) {
+	if w.build != nil {
+		return w.build(b)
+	}
+	if w.named {
+		return w.renderNamed(b)
+	}
+
+	t := w.template
+	argi := 0
+
+	var sb strings.Builder
+	sb.Grow(len(t) + 8)
+	for i := 0; i < len(t); i++ {
+		if end := skipQuoted(t, i); end > i {
+			sb.WriteString(t[i:end])
+			i = end - 1
+			continue
+		}
+		if t[i] != '?' {
+			sb.WriteByte(t[i])
+			continue
+		}
+		// "??" is an escaped literal "?", e.g. the jsonb ? operator
+		if i+1 < len(t) && t[i+1] == '?' {
+			sb.WriteByte('?')
+			i++
+			continue
+		}
+		if argi < len(w.Args) {
+			ph, err := b.bindList(w.Args[argi], sb.String())
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(ph)
+			argi++
+			continue
+		}
+		sb.WriteString(b.placeholder())
 	}
-	return result, bind
+	b.args = append(b.args, w.Args[argi:]...)
+	return sb.String(), nil
+}
+
+// renderWhere joins the non-nil predicates with AND and renders them as a
+// " WHERE ..." clause using b. User templates are parenthesized when
+// combined with other predicates. Returns "" if there is nothing to render.
+func renderWhere(b *binder, preds ...*whereOption) (string, error) {
+	parts := make([]string, 0, len(preds))
+	grouped := make([]bool, 0, len(preds))
+	for _, p := range preds {
+		if p == nil {
+			continue
+		}
+		sql, err := p.render(b)
+		if err != nil {
+			return "", err
+		}
+		if sql == "" {
+			continue
+		}
+		parts = append(parts, sql)
+		grouped = append(grouped, p.grouped)
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", nil
+	case 1:
+		return " WHERE " + parts[0], nil
+	}
+
+	for i := range parts {
+		if !grouped[i] {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return " WHERE " + strings.Join(parts, " AND "), nil
 }
 
 func (opt excludeOption) Type() OptionType { return ExcludeOption }
 func (opt excludeOption) Value() any       { return string(opt) }
 
-// Exclude creates an option that excludes the named fields (comma-separated
-// db column names) from the result.
+// Exclude creates an option that excludes the named fields (comma-separated,
+// any name format) from the result. Unknown names are ignored unless
+// [Config.Strict] is set.
 //
 //	m.Fields(norm.Exclude("id,password"))
+//	m.Fields(norm.Exclude("Id, PasswordHash"))
 func Exclude(fields string) Option {
 	return excludeOption(fields)
 }
@@ -83,10 +216,11 @@ func Exclude(fields string) Option {
 func (opt fieldsOption) Type() OptionType { return FieldsOption }
 func (opt fieldsOption) Value() any       { return string(opt) }
 
-// Fields creates an option that includes only the named fields (comma-separated
-// db column names).
+// Fields creates an option that includes only the named fields (comma-separated,
+// any name format). Unknown names are ignored unless [Config.Strict] is set.
 //
 //	m.Fields(norm.Fields("name,email"))
+//	m.Fields(norm.Fields("Name, createdAt"))
 func Fields(fields string) Option {
 	return fieldsOption(fields)
 }
@@ -95,9 +229,10 @@ func (opt returningOption) Type() OptionType { return ReturningOption }
 func (opt returningOption) Value() any       { return string(opt) }
 
 // Returning creates an option that adds a RETURNING clause with the given
-// field names (comma-separated, any name format).
+// field names (comma-separated, any name format). "*" returns all columns.
 //
 //	m.Insert(norm.Returning("Id"))
+//	m.Delete(norm.Where("id = ?", id), norm.Returning("*"))
 func Returning(fields string) Option {
 	return returningOption(fields)
 }
@@ -114,20 +249,30 @@ func Prefix(prefix string) prefixOption {
 }
 
 // BuildWhere renders a WHERE clause string, replacing "?" placeholders with
-// "$N" starting from startBind. Returns the rendered string and the args
-// slice unchanged. Useful for building UPDATE queries manually.
+// "$N" starting from startBind. Quoted literals and comments are skipped and
+// "??" renders a literal "?", like in [Where]. Returns the rendered string
+// and the args, with slice arguments expanded like in [Where].
+// Useful for building UPDATE queries manually. BuildWhere always renders
+// PostgreSQL placeholders; use [Model.BuildWhere] to follow the
+// [Config.Dialect] of a model.
 //
 //	set, nextBind := m.UpdateFields(norm.Exclude("id"))
 //	whereStr, whereArgs := norm.BuildWhere(nextBind, "id = ?", user.Id)
 func BuildWhere(startBind int, where string, args ...any) (string, []any) {
-	result := where
-	bind := startBind
-	count := strings.Count(result, "?")
-	for i := 0; i < count; i++ {
-		result = strings.Replace(result, "?", fmt.Sprintf("$%d", bind), 1)
-		bind++
+	return buildWhere(newBinder(startBind), where, args)
+}
+
+// buildWhere renders a WHERE template with b for the BuildWhere helpers.
+func buildWhere(b *binder, where string, args []any) (string, []any) {
+	w := parseWhere(where, args...)
+	if w == nil {
+		return "", args
+	}
+	result, err := w.render(b)
+	if err != nil {
+		panic("BuildWhere: " + err.Error())
 	}
-	return result, args
+	return result, b.args
 }
 
 func (opt *whereOption) Type() OptionType { return WhereOption }
@@ -135,12 +280,124 @@ func (opt *whereOption) Value() any       { return opt }
 
 // Where creates an option that adds a WHERE clause. Use "?" as placeholders
 // for positional arguments — they are replaced with $1, $2, etc. at build time.
+// Question marks inside string literals, quoted identifiers, dollar-quoted
+// strings and comments are left alone; write "??" for a literal "?", e.g.
+// the jsonb operators ?, ?| and ?&.
+//
+// A slice argument is expanded into one placeholder per element and the
+// following placeholders are shifted; an empty slice renders as NULL, and
+// is an error right after "NOT IN (", where NULL would match no rows.
+// Byte slices, [database/sql/driver.Valuer] values and slices right after
+// "ANY(", "ALL(" or "SOME(" are never expanded.
+// Wrap a value with [Array] to pass a slice as one argument, or set
+// [Config.SlicesAsArrays] to do so for all slices.
 //
 //	m.Select(norm.Where("name = ? AND age > ?", "John", 18))
+//	m.Select(norm.Where("tags ?? ? AND note = 'why?'", "x"))
+//	// "... WHERE tags ? $1 AND note = 'why?'"
+//	m.Select(norm.Where("id IN (?) AND status = ?", []int{1, 2, 3}, "active"))
+//	// "... WHERE id IN ($1, $2, $3) AND status = $4"
 func Where(where string, args ...any) Option {
 	return parseWhere(where, args...)
 }
 
+// arrayArg marks a template argument that is bound as a single value.
+type arrayArg struct {
+	value any
+}
+
+// Array marks a slice argument of [Where] or [WhereNamed] to be passed as
+// one array argument instead of being expanded, for use with = ANY(?) or
+// array operators.
+//
+//	m.Select(norm.Where("id = ANY(?) AND tags && ?", norm.Array(ids), norm.Array(tags)))
+//	// "... WHERE id = ANY($1) AND tags && $2"
+func Array(v any) any {
+	return arrayArg{value: v}
+}
+
+func (opt whereCondsOption) Type() OptionType { return WhereCondsOption }
+func (opt whereCondsOption) Value() any       { return []Cond(opt) }
+
+// WhereConds creates an option that adds WHERE conditions built from typed
+// [Cond] values, joined with AND. Field names are resolved like in
+// [Model.BuildConditions]; in [Config.Strict] mode an unknown field makes
+// the builder return an error. Bind numbers continue after any
+// values that precede the WHERE clause (e.g. the SET list of an UPDATE).
+// Can be combined with [Where]; repeated WhereConds options accumulate.
+//
+//	m.Update(norm.Exclude("id"), norm.WhereConds(norm.Eq("Id", 1), norm.Gt("Age", 18)))
+//	// "UPDATE users SET name=$1, age=$2 WHERE id=$3 AND age > $4"
+func WhereConds(conds ...Cond) Option {
+	return whereCondsOption(conds)
+}
+
+func (opt totalOption) Type() OptionType { return TotalOption }
+func (opt totalOption) Value() any       { return opt.target }
+
+// WithTotal creates an option that adds count(*) OVER() to the select list
+// of [Model.Select], so every row carries the number of rows matching the
+// WHERE clause before LIMIT/OFFSET. Pass the same option to
+// [Model.Pointers] to scan the count into target, a pointer to an integer.
+//
+//	var total int64
+//	withTotal := norm.WithTotal(&total)
+//	sql, args, _ := m.Select(norm.Where("active = ?", true), norm.Limit(20), withTotal)
+//	// "SELECT id, name, count(*) OVER() FROM users WHERE active = $1 LIMIT 20"
+//	err := rows.Scan(m.Pointers(withTotal)...)
+func WithTotal(target any) Option {
+	return totalOption{target: target}
+}
+
+func (opt groupByOption) Type() OptionType { return GroupByOption }
+func (opt groupByOption) Value() any       { return string(opt) }
+
+// GroupBy creates an option that adds a GROUP BY clause with the given
+// fields (comma-separated, any name format). Unknown fields are an error.
+// Unless [Fields] is given, the grouped fields become the select list, so
+// Select and Pointers only cover them and the [Agg] items.
+//
+//	m.Select(norm.GroupBy("Status"), norm.Agg("count", "*", "n"))
+//	// "SELECT status, count(*) AS n FROM orders GROUP BY status"
+func GroupBy(fields string) Option {
+	return groupByOption(fields)
+}
+
+func (opt distinctOption) Type() OptionType { return DistinctOption }
+func (opt distinctOption) Value() any       { return opt.on }
+
+// Distinct creates an option that renders SELECT DISTINCT.
+//
+//	m.Select(norm.Fields("status"), norm.Distinct())
+//	// "SELECT DISTINCT status FROM orders"
+func Distinct() Option {
+	return distinctOption{}
+}
+
+// DistinctOn creates an option that renders SELECT DISTINCT ON (...) with
+// the given fields (comma-separated, any name format), keeping the first
+// row of each group in the [Order] of the query. Unknown fields are an
+// error, and so is an ORDER BY that does not start with the DISTINCT ON
+// fields, which PostgreSQL rejects.
+//
+//	m.Select(norm.DistinctOn("UserId"), norm.Order("UserId, CreatedAt DESC"))
+//	// "SELECT DISTINCT ON (user_id) id, user_id, ... ORDER BY user_id ASC, created_at DESC"
+func DistinctOn(fields string) Option {
+	return distinctOption{on: fields}
+}
+
+func (opt havingOption) Type() OptionType { return HavingOption }
+func (opt havingOption) Value() any       { return opt.where }
+
+// Having creates an option that adds a HAVING clause. Placeholders work
+// like in [Where]; bind numbers continue after the WHERE clause.
+//
+//	m.Select(norm.GroupBy("UserId"), norm.Agg("count", "*", "n"), norm.Having("count(*) > ?", 5))
+//	// "SELECT user_id, count(*) AS n FROM orders GROUP BY user_id HAVING count(*) > $1"
+func Having(having string, args ...any) Option {
+	return havingOption{where: parseWhere(having, args...)}
+}
+
 func (opt addTargetsOption) Type() OptionType { return AddTargetsOption }
 func (opt addTargetsOption) Value() any       { return []any(opt) }
 
@@ -185,6 +442,127 @@ func Order(orderBy string) Option {
 	return orderByOption(orderBy)
 }
 
+func (opt *onConflictOption) Type() OptionType { return OnConflictOption }
+func (opt *onConflictOption) Value() any       { return opt }
+
+// Conflict describes the conflict target of an INSERT ... ON CONFLICT clause.
+// Create it with [OnConflict] or [OnConstraint] and finish it with
+// [Conflict.DoUpdate] or [Conflict.DoNothing].
+type Conflict struct {
+	fields     []string
+	constraint string
+}
+
+// OnConflict starts an ON CONFLICT clause with the given conflict target
+// fields (any name format). Without fields the target defaults to the
+// model's pk columns.
+//
+//	m.Insert(norm.Exclude("id"), norm.OnConflict("Email").DoUpdate(norm.Exclude("created_at")))
+//	// "... ON CONFLICT (email) DO UPDATE SET name=EXCLUDED.name, age=EXCLUDED.age"
+func OnConflict(fields ...string) Conflict {
+	return Conflict{fields: fields}
+}
+
+// OnConstraint starts an ON CONFLICT ON CONSTRAINT clause for the named
+// constraint.
+//
+//	m.Insert(norm.OnConstraint("users_email_key").DoNothing())
+func OnConstraint(name string) Conflict {
+	return Conflict{constraint: name}
+}
+
+// DoUpdate finishes the clause with DO UPDATE SET col=EXCLUDED.col for every
+// inserted column except the conflict target and autoCreateTime columns.
+// Supports [Exclude] and [Fields] options to narrow the SET list.
+func (c Conflict) DoUpdate(opts ...Option) Option {
+	return &onConflictOption{
+		fields:     c.fields,
+		constraint: c.constraint,
+		doUpdate:   true,
+		update:     opts,
+	}
+}
+
+// DoNothing finishes the clause with DO NOTHING.
+func (c Conflict) DoNothing() Option {
+	return &onConflictOption{fields: c.fields, constraint: c.constraint}
+}
+
+// OnConflictDoNothing creates an option that adds ON CONFLICT DO NOTHING
+// without a conflict target, ignoring any unique violation.
+//
+//	m.Insert(norm.OnConflictDoNothing())
+func OnConflictDoNothing() Option {
+	return &onConflictOption{noTarget: true}
+}
+
+func (opt *keysetOption) Type() OptionType { return KeysetOption }
+func (opt *keysetOption) Value() any       { return opt }
+
+// After creates a keyset pagination option for [Model.Select] that selects
+// the rows following cursor in the [Order] of the query. The cursor holds
+// the values of the ORDER BY columns of the last row of the previous page,
+// see [Model.EncodeCursor] and [Model.DecodeCursor].
+//
+//	m.Select(norm.Order("CreatedAt DESC, Id DESC"), norm.After(cursor), norm.Limit(50))
+//	// "... WHERE (created_at, id) < ($1, $2) ORDER BY created_at DESC, id DESC LIMIT 50"
+func After(cursor Cursor) Option {
+	return &keysetOption{cursor: cursor}
+}
+
+// Before creates a keyset pagination option for [Model.Select] that selects
+// the rows preceding cursor. The ORDER BY is reversed so that [Limit] keeps
+// the rows closest to the cursor; reverse the scanned rows to restore the
+// requested order.
+//
+//	m.Select(norm.Order("CreatedAt DESC, Id DESC"), norm.Before(cursor), norm.Limit(50))
+//	// "... WHERE (created_at, id) > ($1, $2) ORDER BY created_at ASC, id ASC LIMIT 50"
+func Before(cursor Cursor) Option {
+	return &keysetOption{cursor: cursor, before: true}
+}
+
+const (
+	excludeDeleted deletedScope = iota // default: hide soft-deleted rows
+	withDeleted                        // include soft-deleted rows
+	onlyDeleted                        // only soft-deleted rows
+)
+
+func (opt deletedScope) Type() OptionType { return DeletedScopeOption }
+func (opt deletedScope) Value() any       { return int(opt) }
+
+// deletedScope also implements Cond so WithDeleted() and OnlyDeleted()
+// work in BuildConditions.
+func (opt deletedScope) isCond() {}
+
+// WithDeleted creates an option that disables the automatic
+// "deleted_at IS NULL" filter for models with a softdelete field.
+// Also usable in [BuildConditions].
+//
+//	m.Select(norm.WithDeleted())
+func WithDeleted() deletedScope {
+	return withDeleted
+}
+
+// OnlyDeleted creates an option that selects only soft-deleted rows
+// ("deleted_at IS NOT NULL"). Also usable in [BuildConditions].
+//
+//	m.Select(norm.OnlyDeleted())
+func OnlyDeleted() deletedScope {
+	return onlyDeleted
+}
+
+func (opt hardDeleteOption) Type() OptionType { return HardDeleteOption }
+func (opt hardDeleteOption) Value() any       { return true }
+
+// HardDelete creates an option that makes [Model.Delete] render a real
+// DELETE for models with a softdelete field. Soft-deleted rows are not
+// filtered out unless [OnlyDeleted] is also given.
+//
+//	m.Delete(norm.HardDelete(), norm.Where("id = ?", 42))
+func HardDelete() Option {
+	return hardDeleteOption{}
+}
+
 // ComposedOptions holds the parsed result of all options passed to a method.
 type ComposedOptions struct {
 	Exclude    []string
@@ -196,6 +574,19 @@ type ComposedOptions struct {
 	Offset     int
 	Limit      int
 	OrderBy    string
+	OnConflict *onConflictOption
+	Deleted    deletedScope
+	HardDelete bool
+	WhereConds []Cond
+	Keyset     *keysetOption
+	Total      any // scan target for count(*) OVER(), nil if not requested
+	GroupBy    []string
+	Having     *whereOption
+	Aggs       []*Aggregate
+	Lock       Lock
+	Distinct   bool
+	DistinctOn []string
+	With       []*cte
 }
 
 // ComposeOptions parses a list of [Option] values into a single [ComposedOptions].
@@ -230,6 +621,34 @@ func ComposeOptions(opts ...Option) ComposedOptions {
 			res.Limit = int(opt)
 		case orderByOption:
 			res.OrderBy = string(opt)
+		case *onConflictOption:
+			res.OnConflict = opt
+		case deletedScope:
+			res.Deleted = opt
+		case hardDeleteOption:
+			res.HardDelete = true
+		case whereCondsOption:
+			res.WhereConds = append(res.WhereConds, opt...)
+		case *keysetOption:
+			res.Keyset = opt
+		case totalOption:
+			res.Total = opt.target
+		case groupByOption:
+			res.GroupBy = strings.Split(string(opt), ",")
+		case havingOption:
+			res.Having = opt.where
+		case *Aggregate:
+			res.Aggs = append(res.Aggs, opt)
+		case Lock:
+			res.Lock = opt
+		case *cte:
+			res.With = append(res.With, opt)
+		case distinctOption:
+			res.Distinct = true
+			res.DistinctOn = nil
+			if opt.on != "" {
+				res.DistinctOn = strings.Split(opt.on, ",")
+			}
 		}
 	}
 
@@ -92,6 +92,26 @@ func TestBuildWhere(t *testing.T) {
 	})
 }
 
+func TestBuildRenderError(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"missing named param", WhereNamed("id = :id", map[string]any{})},
+		{"struct without model", WhereStruct("id = :Id")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tt.opt.(*whereOption).Build(1)
+		})
+	}
+}
+
 func TestComposeOptions(t *testing.T) {
 	t.Run("empty options", func(t *testing.T) {
 		co := ComposeOptions()
@@ -193,6 +213,18 @@ func TestOptionTypes(t *testing.T) {
 		{"addTargets", AddTargets(new(int)), AddTargetsOption},
 		{"offset", Offset(10), OffsetOption},
 		{"limit", Limit(5), LimitOption},
+		{"onConflict", OnConflict("id").DoNothing(), OnConflictOption},
+		{"withDeleted", WithDeleted(), DeletedScopeOption},
+		{"hardDelete", HardDelete(), HardDeleteOption},
+		{"whereConds", WhereConds(Eq("id", 1)), WhereCondsOption},
+		{"after", After(Cursor{Values: []any{1}}), KeysetOption},
+		{"withTotal", WithTotal(new(int64)), TotalOption},
+		{"groupBy", GroupBy("id"), GroupByOption},
+		{"having", Having("count(*) > ?", 1), HavingOption},
+		{"agg", Agg("count", "*", "n"), AggregateOption},
+		{"lock", ForUpdate(), LockOption},
+		{"distinct", Distinct(), DistinctOption},
+		{"with", With("t", Query{}), CTEOption},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,137 @@
+package norm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SelectByPK builds a SELECT query for the row identified by the bound
+// struct's pk fields. Composite keys are joined with AND. An extra [Where]
+// option is combined with the pk condition.
+// Supports the same options as [Model.Select].
+//
+//	user := User{Id: 42}
+//	m, _ := orm.M(&user)
+//	sql, args, _ := m.SelectByPK()
+//	// "SELECT id, name, email FROM users WHERE id=$1"
+func (m *Model) SelectByPK(opts ...Option) (string, []any, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	pred, err := m.pkWhere("SelectByPK")
+	if err != nil {
+		return "", nil, err
+	}
+
+	ff, co, err := m.filteredFields("SelectByPK", opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return m.selectSQL(ff, co, pred)
+}
+
+// UpdateByPK builds an UPDATE query for the row identified by the bound
+// struct's pk fields. Pk columns are never included in the SET list.
+// Supports the same options as [Model.Update].
+//
+//	sql, args, _ := m.UpdateByPK()
+//	// "UPDATE users SET name=$1, email=$2 WHERE id=$3"
+func (m *Model) UpdateByPK(opts ...Option) (string, []any, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	pred, err := m.pkWhere("UpdateByPK")
+	if err != nil {
+		return "", nil, err
+	}
+
+	ff, co, err := m.filteredFields("UpdateByPK", opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	set := make([]*Field, 0, len(ff))
+	for _, f := range ff {
+		if !has(m.pk, f.dbName) {
+			set = append(set, f)
+		}
+	}
+
+	return m.updateSQL("UpdateByPK", set, co, pred)
+}
+
+// DeleteByPK builds a DELETE query for the row identified by the bound
+// struct's pk fields. Supports the same options as [Model.Delete].
+//
+//	sql, args, _ := m.DeleteByPK()
+//	// "DELETE FROM users WHERE id=$1"
+func (m *Model) DeleteByPK(opts ...Option) (string, []any, error) {
+	co := ComposeOptions(opts...)
+
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	pred, err := m.pkWhere("DeleteByPK")
+	if err != nil {
+		return "", nil, err
+	}
+
+	return m.deleteSQL(co, pred)
+}
+
+// ExistsByPK builds a query that reports whether the row identified by the
+// bound struct's pk fields exists. Scan the result into a bool.
+// Supports [Where], [WhereConds], [WithDeleted], and [OnlyDeleted] options.
+//
+//	sql, args, _ := m.ExistsByPK()
+//	// "SELECT EXISTS (SELECT 1 FROM users WHERE id=$1)"
+//	var exists bool
+//	err := pool.QueryRow(ctx, sql, args...).Scan(&exists)
+func (m *Model) ExistsByPK(opts ...Option) (string, []any, error) {
+	co := ComposeOptions(opts...)
+
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	pred, err := m.pkWhere("ExistsByPK")
+	if err != nil {
+		return "", nil, err
+	}
+
+	b := m.newBinder()
+	where, err := renderWhere(b, pred, m.structWhere(co.Where), m.whereConds("ExistsByPK", co, ""),
+		m.softDeleteWhere("", co.Deleted))
+	if err != nil {
+		return "", nil, err
+	}
+	sql := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s%s)", m.ident(m.table), where)
+
+	return sql, b.args, nil
+}
+
+// pkWhere returns a predicate matching the bound struct's pk values.
+// Must be called under m.mut.RLock.
+func (m *Model) pkWhere(method string) (*whereOption, error) {
+	if len(m.pk) == 0 {
+		return nil, fmt.Errorf("%s: model %q has no pk fields", method, m.table)
+	}
+
+	conds := make([]string, 0, len(m.pk))
+	args := make([]any, 0, len(m.pk))
+	for _, name := range m.pk {
+		f := m.fieldByAnyName[name]
+		val, err := m.fieldValue(m.val, f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", method, err)
+		}
+		conds = append(conds, m.ident(f.dbName)+"=?")
+		args = append(args, val)
+	}
+
+	return &whereOption{
+		template: strings.Join(conds, " AND "),
+		Args:     args,
+		grouped:  true,
+	}, nil
+}
@@ -0,0 +1,141 @@
+package norm
+
+import "testing"
+
+type CompositePKStruct struct {
+	TenantId int `norm:"pk"`
+	UserId   int `norm:"pk"`
+	Role     string
+}
+
+func TestSelectByPK(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&ModelTestStruct{Id: 42})
+
+	t.Run("single pk", func(t *testing.T) {
+		sql, args, err := m.SelectByPK()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id, name, email, age FROM model_test_struct WHERE id=$1"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 1 || args[0] != 42 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("combined with where", func(t *testing.T) {
+		sql, args, err := m.SelectByPK(Fields("name"), Where("age > ? OR age IS NULL", 18))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT name FROM model_test_struct WHERE id=$1 AND (age > $2 OR age IS NULL)"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 2 || args[0] != 42 || args[1] != 18 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("composite pk", func(t *testing.T) {
+		mc, _ := n.M(&CompositePKStruct{TenantId: 1, UserId: 2})
+		sql, args, err := mc.SelectByPK()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT tenant_id, user_id, role FROM composite_pk_struct WHERE tenant_id=$1 AND user_id=$2"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 2 || args[0] != 1 || args[1] != 2 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("no pk errors", func(t *testing.T) {
+		mn, _ := n.M(&NoPKStruct{})
+		_, _, err := mn.SelectByPK()
+		if err == nil {
+			t.Error("expected error for model without pk")
+		}
+	})
+}
+
+func TestUpdateByPK(t *testing.T) {
+	n := NewNorm(nil)
+
+	t.Run("excludes pk from set", func(t *testing.T) {
+		m, _ := n.M(&ModelTestStruct{Id: 1, Name: "Bob", Email: "bob@test.com", Age: 30})
+		sql, args, err := m.UpdateByPK(Returning("Id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "UPDATE model_test_struct SET name=$1, email=$2, age=$3 WHERE id=$4 RETURNING id"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 4 || args[0] != "Bob" || args[3] != 1 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("composite pk with fields", func(t *testing.T) {
+		mc, _ := n.M(&CompositePKStruct{TenantId: 1, UserId: 2, Role: "admin"})
+		sql, args, err := mc.UpdateByPK(Fields("role,tenant_id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "UPDATE composite_pk_struct SET role=$1 WHERE tenant_id=$2 AND user_id=$3"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 3 || args[0] != "admin" {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("only pk fields errors", func(t *testing.T) {
+		m, _ := n.M(&ModelTestStruct{Id: 1})
+		_, _, err := m.UpdateByPK(Fields("id"))
+		if err == nil {
+			t.Error("expected error when only pk fields are selected")
+		}
+	})
+}
+
+func TestDeleteByPK(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&ModelTestStruct{Id: 7})
+
+	sql, args, err := m.DeleteByPK(Returning("Name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "DELETE FROM model_test_struct WHERE id=$1 RETURNING name"
+	if sql != want {
+		t.Errorf("got %q", sql)
+	}
+	if len(args) != 1 || args[0] != 7 {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestExistsByPK(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&ModelTestStruct{Id: 7})
+
+	sql, args, err := m.ExistsByPK()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT EXISTS (SELECT 1 FROM model_test_struct WHERE id=$1)"
+	if sql != want {
+		t.Errorf("got %q", sql)
+	}
+	if len(args) != 1 || args[0] != 7 {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
@@ -0,0 +1,208 @@
+package norm
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Query is a rendered SQL statement together with its positional arguments.
+//
+//	for _, q := range queries {
+//	    _, err := pool.Exec(ctx, q.SQL, q.Args...)
+//	}
+type Query struct {
+	SQL  string
+	Args []any
+
+	err      error   // build error of a subquery, reported by the outer statement
+	cols     int     // number of output columns of a SELECT, 0 if unknown
+	dialect  Dialect // dialect the query was built in, nil if unknown
+	compound bool    // built by Compound.SubSelect
+}
+
+// binder collects bind arguments while a statement is being rendered and
+// hands out sequential placeholders ($1, $2, ...).
+type binder struct {
+	next       int
+	args       []any
+	arrays     bool // pass slices in templates as one argument, see Config.SlicesAsArrays
+	dialect    Dialect
+	positional bool // placeholders are not numbered, see rebind
+	now        any  // Config.NowFunc value of the statement, nil until first used
+}
+
+// newBinder creates a [Postgres] binder whose first placeholder is $start.
+func newBinder(start int) *binder {
+	return &binder{next: start, dialect: Postgres}
+}
+
+// newDialectBinder creates a binder for a statement in dialect d,
+// starting at the first placeholder.
+func newDialectBinder(d Dialect) *binder {
+	return &binder{next: 1, dialect: d, positional: positional(d)}
+}
+
+// newBinder creates a binder for a statement of this model, starting at $1
+// and following the model's [Config].
+func (m *modelMeta) newBinder() *binder {
+	b := newDialectBinder(m.config.Dialect)
+	b.arrays = m.config.SlicesAsArrays
+	return b
+}
+
+// bind appends v to the argument list and returns its placeholder.
+func (b *binder) bind(v any) string {
+	b.args = append(b.args, v)
+	return b.placeholder()
+}
+
+// rebind returns the placeholder p of an argument bound earlier, or binds
+// v again for dialects whose placeholders are not numbered.
+func (b *binder) rebind(p string, v any) string {
+	if b.positional {
+		return b.bind(v)
+	}
+	return p
+}
+
+// placeholder returns the next placeholder without adding an argument.
+func (b *binder) placeholder() string {
+	p := b.dialect.Placeholder(b.next)
+	b.next++
+	return p
+}
+
+// bindList binds a template argument. Slices are expanded into one
+// placeholder per element ("$1, $2, $3"), an empty slice renders as NULL.
+// Byte slices, [driver.Valuer] values and [Array] arguments are bound as a
+// single value, as are all slices when b.arrays is set.
+//
+// rendered is the template text before the argument. A slice right after
+// "ANY(", "ALL(" or "SOME(" is an array operand and is bound as one value.
+// An empty slice right after "NOT IN (" is an error: NOT IN (NULL) matches
+// no rows, while an empty exclusion list should match all of them.
+func (b *binder) bindList(v any, rendered string) (string, error) {
+	if a, ok := v.(arrayArg); ok {
+		return b.bind(a.value), nil
+	}
+	if b.arrays || afterArrayOp(rendered) {
+		return b.bind(v), nil
+	}
+	if _, ok := v.(driver.Valuer); ok {
+		return b.bind(v), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return b.bind(v), nil
+	}
+	if rv.Len() == 0 {
+		if afterNotIn(rendered) {
+			return "", errors.New("Where: empty slice in NOT IN (?) would match no rows, use NotIn or omit the condition")
+		}
+		return "NULL", nil
+	}
+
+	placeholders := make([]string, rv.Len())
+	for i := range placeholders {
+		placeholders[i] = b.bind(rv.Index(i).Interface())
+	}
+	return strings.Join(placeholders, ", "), nil
+}
+
+// afterNotIn reports whether sql ends with "NOT IN (", in any case and
+// spacing.
+func afterNotIn(sql string) bool {
+	w1, w2 := lastWords(sql)
+	return strings.EqualFold(w1, "NOT") && strings.EqualFold(w2, "IN")
+}
+
+// afterArrayOp reports whether sql ends with "ANY (", "ALL (" or "SOME (",
+// in any case and spacing.
+func afterArrayOp(sql string) bool {
+	_, w := lastWords(sql)
+	return strings.EqualFold(w, "ANY") || strings.EqualFold(w, "ALL") || strings.EqualFold(w, "SOME")
+}
+
+// lastWords returns the two words before an opening parenthesis at the
+// end of sql, or "" if sql does not end with one. A word is a run of
+// identifier characters, so "id=ANY(" yields "id", "ANY".
+func lastWords(sql string) (string, string) {
+	sql = strings.TrimRight(sql, " \t\r\n")
+	if !strings.HasSuffix(sql, "(") {
+		return "", ""
+	}
+	word := func(s string) (string, string) {
+		s = strings.TrimRight(s, " \t\r\n")
+		i := len(s)
+		for i > 0 && isNameByte(s[i-1], false) {
+			i--
+		}
+		return s[:i], s[i:]
+	}
+	rest, w2 := word(sql[:len(sql)-1])
+	_, w1 := word(rest)
+	return w1, w2
+}
+
+// embed renders q inside the statement being built: its placeholders
+// ($1..$n, or "?" if q was built in such a dialect) are renumbered to
+// follow the placeholders handed out so far and its arguments are
+// appended. A query of unknown dialect, written by hand or wrapped with
+// [NewQuery], is read in the dialect of b. Placeholders inside quoted text
+// and comments are left alone, as are those without a matching argument.
+func (b *binder) embed(q Query) string {
+	t := q.SQL
+	src := q.dialect
+	if src == nil {
+		src = b.dialect
+	}
+	seq := positional(src)
+	binds := make(map[int]string, len(q.Args))
+	next := 0 // next argument of a positional query
+
+	var sb strings.Builder
+	for i := 0; i < len(t); {
+		if end := skipQuoted(t, i); end > i {
+			sb.WriteString(t[i:end])
+			i = end
+			continue
+		}
+
+		if seq {
+			if t[i] == '?' && next < len(q.Args) {
+				sb.WriteString(b.bind(q.Args[next]))
+				next++
+			} else {
+				sb.WriteByte(t[i])
+			}
+			i++
+			continue
+		}
+
+		j := i + 1
+		for t[i] == '$' && j < len(t) && t[j] >= '0' && t[j] <= '9' {
+			j++
+		}
+		n, err := strconv.Atoi(t[i+1 : j])
+		if t[i] != '$' || err != nil || n < 1 || n > len(q.Args) {
+			sb.WriteByte(t[i])
+			i++
+			continue
+		}
+
+		p, ok := binds[n]
+		if ok {
+			p = b.rebind(p, q.Args[n-1])
+		} else {
+			p = b.bind(q.Args[n-1])
+			binds[n] = p
+		}
+		sb.WriteString(p)
+		i = j
+	}
+	return sb.String()
+}
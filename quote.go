@@ -0,0 +1,89 @@
+package norm
+
+import "strings"
+
+// reservedWords are keywords that cannot be used as unquoted identifiers
+// in PostgreSQL, SQLite or MySQL, lowercase.
+var reservedWords = func() map[string]bool {
+	words := strings.Fields(`
+		add all alter analyse analyze and any array as asc asymmetric
+		authorization between binary both by case cast change check collate
+		collation column concurrently condition constraint create cross
+		current_catalog current_date current_role current_schema current_time
+		current_timestamp current_user database default deferrable delete desc
+		distinct div do drop else end except exists explain false fetch for
+		foreign freeze from full grant group having if ignore ilike in index
+		initially inner insert intersect interval into is isnull join key keys
+		kill lateral leading left like limit localtime localtimestamp lock
+		match mod natural not notnull null offset on only option or order
+		outer overlaps placing primary range rank read references release
+		rename replace require returning right row rows schema select
+		session_user set show similar some symmetric system_user table
+		tablesample then to trailing true union unique update usage use user
+		using values variadic verbose when where window with write`)
+	res := make(map[string]bool, len(words))
+	for _, w := range words {
+		res[w] = true
+	}
+	return res
+}()
+
+// needsQuote reports whether name must be quoted to be used as an
+// identifier: reserved words, names with uppercase letters (folded to
+// lowercase when unquoted) and names starting with a digit.
+func needsQuote(name string) bool {
+	if name == "" || reservedWords[strings.ToLower(name)] {
+		return true
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		return true
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_') {
+			return true
+		}
+	}
+	return false
+}
+
+// ident returns a table or column name ready for use in SQL, quoted with
+// the dialect if it needs quoting or [Config.QuoteIdentifiers] is set.
+// The parts of a schema-qualified name are quoted separately.
+func (c *Config) ident(name string) string {
+	if schema, table := splitTable(name); schema != "" {
+		return c.ident(schema) + "." + c.ident(table)
+	}
+	if c.QuoteIdentifiers || needsQuote(name) {
+		return c.Dialect.Quote(name)
+	}
+	return name
+}
+
+// ident returns a table or column name ready for use in SQL, see
+// [Norm.QuoteIdent].
+func (m *modelMeta) ident(name string) string {
+	return m.config.ident(name)
+}
+
+// identList renders a parenthesized list of column names.
+func (m *modelMeta) identList(names []string) string {
+	res := make([]string, len(names))
+	for i, name := range names {
+		res[i] = m.ident(name)
+	}
+	return "(" + strings.Join(res, ", ") + ")"
+}
+
+// QuoteIdent returns a table or column name as it appears in generated
+// SQL: quoted with the [Config.Dialect] if it is a reserved word, contains
+// uppercase letters or starts with a digit, or always if
+// [Config.QuoteIdentifiers] is set.
+//
+//	orm.QuoteIdent("order")   // `"order"`
+//	orm.QuoteIdent("userId")  // `"userId"`
+//	orm.QuoteIdent("user_id") // "user_id"
+//	orm.QuoteIdent("billing.User") // `billing."User"`
+func (n *Norm) QuoteIdent(name string) string {
+	return n.config.ident(name)
+}
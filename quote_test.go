@@ -0,0 +1,156 @@
+package norm
+
+import (
+	"strings"
+	"testing"
+)
+
+type User struct {
+	Id     int `norm:"pk"`
+	Name   string
+	Order  int
+	UserId int `norm:"dbName=userId"`
+}
+
+type Group struct {
+	Id     int `norm:"pk"`
+	UserId int `norm:"fk=User"`
+}
+
+func TestNeedsQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"user_id", false},
+		{"t1", false},
+		{"user", true},
+		{"ORDER", true},
+		{"userId", true},
+		{"1st", true},
+		{"my-col", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		if got := needsQuote(tt.name); got != tt.want {
+			t.Errorf("needsQuote(%q): got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteIdentifiers(t *testing.T) {
+	n := NewNorm(nil)
+	m, err := n.M(&User{Id: 1, Name: "x", Order: 2, UserId: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		build func() (string, []any, error)
+		want  string
+	}{
+		{"select", func() (string, []any, error) {
+			return m.Select(WhereConds(Eq("Order", 1)), Order("UserId DESC"))
+		}, `SELECT id, name, "order", "userId" FROM "user" WHERE "order"=$1 ORDER BY "userId" DESC`},
+		{"insert", func() (string, []any, error) {
+			return m.Insert(Exclude("id"), OnConflict("UserId").DoUpdate(), Returning("id"))
+		}, `INSERT INTO "user" (name, "order", "userId") VALUES ($1, $2, $3) ON CONFLICT ("userId") DO UPDATE SET name=EXCLUDED.name, "order"=EXCLUDED."order" RETURNING id`},
+		{"update", func() (string, []any, error) {
+			return m.Update(Fields("Order"), Where("id = ?", 1))
+		}, `UPDATE "user" SET "order"=$1 WHERE id = $2`},
+		{"delete", func() (string, []any, error) {
+			return m.Delete(Where("id = ?", 1))
+		}, `DELETE FROM "user" WHERE id = $1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.want {
+				t.Errorf("got:\n  %q\nwant:\n  %q", sql, tt.want)
+			}
+		})
+	}
+
+	if got := m.Fields(); got != `id, name, "order", "userId"` {
+		t.Errorf("Fields: got %q", got)
+	}
+}
+
+func TestQuoteIdentifiersAlways(t *testing.T) {
+	n := NewNorm(&Config{QuoteIdentifiers: true, Dialect: MySQL})
+	m, _ := n.M(&ModelTestStruct{})
+
+	sql, _, err := m.Select(Fields("id,name"), WhereConds(Gt("Age", 18)), Order("Name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT `id`, `name` FROM `model_test_struct` WHERE `age` > ? ORDER BY `name` ASC"
+	if sql != want {
+		t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+	}
+	if got := n.QuoteIdent("id"); got != "`id`" {
+		t.Errorf("QuoteIdent: got %q", got)
+	}
+}
+
+func TestQuoteIdentifiersJoin(t *testing.T) {
+	n := NewNorm(nil)
+	mUser, _ := n.M(&User{})
+	mGroup, _ := n.M(&Group{})
+
+	sql, _, err := NewJoin(mUser).Auto(mGroup).WhereConds(Eq("user.Order", 1)).Select()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range []string{
+		`SELECT "user".id, "user".name, "user"."order", "user"."userId", "group".id, "group".user_id`,
+		`FROM "user" INNER JOIN "group" ON "group".user_id = "user".id`,
+		`WHERE "user"."order"=$1`,
+	} {
+		if !strings.Contains(sql, part) {
+			t.Errorf("expected %q in:\n  %q", part, sql)
+		}
+	}
+}
+
+func TestQuoteCTEAndConstraint(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&User{Id: 1, Name: "John"})
+
+	t.Run("cte name and columns", func(t *testing.T) {
+		q := Query{SQL: "SELECT id, user_id FROM grp"}
+		sql, _, err := m.Select(Fields("Id"), With("Order(Id, userId)", q), Where(`id IN (SELECT "Id" FROM "Order")`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `WITH "Order"("Id", "userId") AS (SELECT id, user_id FROM grp) SELECT id FROM "user" WHERE id IN (SELECT "Id" FROM "Order")`
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+
+	t.Run("plain cte name unchanged", func(t *testing.T) {
+		sql, _, err := m.Count(With("moved(id,user_id)", Query{SQL: "SELECT 1, 2"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(sql, "WITH moved(id, user_id) AS (SELECT 1, 2) ") {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("on constraint", func(t *testing.T) {
+		sql, _, err := m.Insert(Fields("Name"), OnConstraint("User_name_key").DoNothing())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `INSERT INTO "user" (name) VALUES ($1) ON CONFLICT ON CONSTRAINT "User_name_key" DO NOTHING`
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package norm
+
+import (
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// splitTable splits a table name into its schema and unqualified name.
+// schema is empty if the name is not schema-qualified.
+func splitTable(name string) (schema, table string) {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// isValidTableName reports whether name is a valid identifier, optionally
+// qualified with a schema: "invoices" or "billing.invoices".
+func isValidTableName(name string) bool {
+	schema, table := splitTable(name)
+	if schema == "" && strings.Contains(name, ".") {
+		return false
+	}
+	return (schema == "" || isValidIdentifier(schema)) && isValidIdentifier(table)
+}
+
+// qualify prefixes an unqualified table name with [Config.DefaultSchema].
+func (c *Config) qualify(table string) string {
+	if c.DefaultSchema == "" || strings.Contains(table, ".") {
+		return table
+	}
+	return c.DefaultSchema + "." + table
+}
+
+// refTable returns the table referenced by an fk tag value. The struct
+// name is converted to snake_case; a reference without a schema is in the
+// schema of table, the referencing table.
+//
+//	refTable("sales.orders", "Invoice")          // "sales.invoice"
+//	refTable("sales.orders", "billing.Invoice")  // "billing.invoice"
+func refTable(table, ref string) string {
+	refSchema, refName := splitTable(ref)
+	refName = strcase.ToSnake(refName)
+	if refSchema == "" {
+		refSchema, _ = splitTable(table)
+	}
+	if refSchema == "" {
+		return refName
+	}
+	return refSchema + "." + refName
+}
+
+// sameTable reports whether a and b name the same table. A name in the
+// "public" schema, the PostgreSQL default, equals its unqualified form.
+func sameTable(a, b string) bool {
+	return strings.TrimPrefix(a, "public.") == strings.TrimPrefix(b, "public.")
+}
+
+// refersTo reports whether the fk tag value ref of a field of table
+// references target, see [refTable]. With fallback set, a reference
+// without a schema also matches a target of the same name in any schema;
+// callers try that only if nothing matches exactly.
+func refersTo(table, ref, target string, fallback bool) bool {
+	if sameTable(refTable(table, ref), target) {
+		return true
+	}
+	refSchema, refName := splitTable(ref)
+	_, name := splitTable(target)
+	return fallback && refSchema == "" && strcase.ToSnake(refName) == name
+}
+
+// FKTable returns the table referenced by the fk tag of field f of a
+// registered table, or false if f has no fk tag. A reference without a
+// schema ("fk=Invoice") is resolved in the schema of table, or else in
+// the only registered table of that name; use "fk=billing.Invoice" to
+// reference a table in another schema.
+//
+//	orm.FKTable("sales.orders", f) // "sales.invoice"
+func (n *Norm) FKTable(table string, f *Field) (string, bool) {
+	ref, ok := f.Tag("fk")
+	if !ok {
+		return "", false
+	}
+	res := refTable(n.config.qualify(table), ref)
+	if schema, _ := splitTable(ref); schema != "" {
+		return res, true
+	}
+
+	n.mut.RLock()
+	defer n.mut.RUnlock()
+	if _, ok := n.tables[res]; ok {
+		return res, true
+	}
+	var found []string
+	for t := range n.tables {
+		if refersTo(table, ref, t, true) {
+			found = append(found, t)
+		}
+	}
+	if len(found) == 1 {
+		return found[0], true
+	}
+	return res, true
+}
@@ -0,0 +1,210 @@
+package norm
+
+import (
+	"strings"
+	"testing"
+)
+
+type Invoice struct {
+	Id     int `norm:"pk"`
+	Amount int
+}
+
+type Payment struct {
+	Id        int `norm:"pk"`
+	InvoiceId int `norm:"fk=billing.Invoice"`
+}
+
+type PUser struct {
+	Id   int `norm:"pk"`
+	Name string
+}
+
+type Account struct {
+	Id      int `norm:"pk"`
+	PUserId int `norm:"fk=PUser"`
+}
+
+type Charge struct {
+	Id      int `norm:"pk"`
+	PUserId int `norm:"fk=public.PUser"`
+}
+
+func TestIsValidTableName(t *testing.T) {
+	tests := []struct {
+		val  string
+		want bool
+	}{
+		{"invoices", true},
+		{"billing.invoices", true},
+		{".invoices", false},
+		{"billing.", false},
+		{"a.b.c", false},
+		{"billing.invoices; DROP TABLE", false},
+	}
+	for _, tt := range tests {
+		if got := isValidTableName(tt.val); got != tt.want {
+			t.Errorf("isValidTableName(%q) = %v, want %v", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaTable(t *testing.T) {
+	n := NewNorm(nil)
+	m := n.AddModel(&Invoice{Id: 1, Amount: 10}, "billing.invoice")
+
+	tests := []struct {
+		name  string
+		build func() (string, []any, error)
+		want  string
+	}{
+		{"select", func() (string, []any, error) {
+			return m.Select(WhereConds(Gt("Amount", 5)), Lock(ForUpdate().Of(m)))
+		}, "SELECT id, amount FROM billing.invoice WHERE amount > $1 FOR UPDATE OF invoice"},
+		{"insert", func() (string, []any, error) {
+			return m.Insert(Exclude("id"))
+		}, "INSERT INTO billing.invoice (amount) VALUES ($1)"},
+		{"update", func() (string, []any, error) {
+			return m.Update(Fields("amount"), Where("id = ?", 1))
+		}, "UPDATE billing.invoice SET amount=$1 WHERE id = $2"},
+		{"delete", func() (string, []any, error) {
+			return m.Delete(Where("id = ?", 1))
+		}, "DELETE FROM billing.invoice WHERE id = $1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.want {
+				t.Errorf("got:\n  %q\nwant:\n  %q", sql, tt.want)
+			}
+		})
+	}
+
+	if fields := n.FieldsByTable("billing.invoice"); len(fields) != 2 {
+		t.Errorf("FieldsByTable: got %d fields", len(fields))
+	}
+	if got := n.QuoteIdent("billing.User"); got != `billing."User"` {
+		t.Errorf("QuoteIdent: got %q", got)
+	}
+}
+
+func TestDefaultSchema(t *testing.T) {
+	n := NewNorm(&Config{DefaultSchema: "app"})
+	m, _ := n.M(&User{})
+	if m.Table() != "app.user" {
+		t.Errorf("Table: got %q", m.Table())
+	}
+	sql, _, err := m.Select(Fields("id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sql != `SELECT id FROM app."user"` {
+		t.Errorf("got %q", sql)
+	}
+	if n.FieldsByTable("user") == nil {
+		t.Error("FieldsByTable: expected lookup in the default schema")
+	}
+
+	inv := n.AddModel(&Invoice{}, "billing.invoice")
+	if inv.Table() != "billing.invoice" {
+		t.Errorf("Table: got %q", inv.Table())
+	}
+}
+
+func TestSchemaJoin(t *testing.T) {
+	n := NewNorm(nil)
+	mInvoice := n.AddModel(&Invoice{}, "billing.invoice")
+	mPayment := n.AddModel(&Payment{}, "sales.payment")
+
+	sql, _, err := NewJoin(mPayment).Auto(mInvoice).WhereConds(Gt("invoice.Amount", 5), Eq("sales.payment.Id", 1)).Select()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT sales.payment.id, sales.payment.invoice_id, billing.invoice.id, billing.invoice.amount" +
+		" FROM sales.payment INNER JOIN billing.invoice ON sales.payment.invoice_id = billing.invoice.id" +
+		" WHERE billing.invoice.amount > $1 AND sales.payment.id=$2"
+	if sql != want {
+		t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+	}
+
+	// reverse direction
+	sql, _, err = NewJoin(mInvoice).Auto(mPayment).Select()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sql, "INNER JOIN sales.payment ON sales.payment.invoice_id = billing.invoice.id") {
+		t.Errorf("got %q", sql)
+	}
+}
+
+func TestSchemaJoinUnqualified(t *testing.T) {
+	n := NewNorm(nil)
+	mUser, _ := n.M(&PUser{})
+	mAccount := n.AddModel(&Account{}, "billing.account")
+	mCharge := n.AddModel(&Charge{}, "billing.charge")
+
+	tests := []struct {
+		name string
+		join *Join
+		want string
+	}{
+		{"fk without schema", NewJoin(mAccount).Auto(mUser),
+			"INNER JOIN p_user ON billing.account.p_user_id = p_user.id"},
+		{"fk without schema, reverse", NewJoin(mUser).Auto(mAccount),
+			"INNER JOIN billing.account ON billing.account.p_user_id = p_user.id"},
+		{"fk in public schema", NewJoin(mCharge).Auto(mUser),
+			"INNER JOIN p_user ON billing.charge.p_user_id = p_user.id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.join.Select()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("expected %q in:\n  %q", tt.want, sql)
+			}
+		})
+	}
+
+	t.Run("same schema preferred", func(t *testing.T) {
+		mBillingUser := n.AddModel(&PUser{}, "billing.p_user")
+		sql, _, err := NewJoin(mAccount).Auto(mBillingUser).Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(sql, "INNER JOIN billing.p_user ON billing.account.p_user_id = billing.p_user.id") {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("FKTable", func(t *testing.T) {
+		n := NewNorm(nil)
+		n.M(&PUser{})
+		m := n.AddModel(&Account{}, "billing.account")
+		f, _ := m.FieldByName("PUserId")
+		if got, _ := n.FKTable("billing.account", f); got != "p_user" {
+			t.Errorf("got %q", got)
+		}
+	})
+}
+
+func TestRefTable(t *testing.T) {
+	tests := []struct {
+		table, ref string
+		want       string
+	}{
+		{"payment", "Invoice", "invoice"},
+		{"sales.payment", "Invoice", "sales.invoice"},
+		{"payment", "billing.Invoice", "billing.invoice"},
+		{"sales.payment", "billing.invoice", "billing.invoice"},
+	}
+	for _, tt := range tests {
+		if got := refTable(tt.table, tt.ref); got != tt.want {
+			t.Errorf("refTable(%q, %q) = %q, want %q", tt.table, tt.ref, got, tt.want)
+		}
+	}
+}
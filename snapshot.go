@@ -0,0 +1,125 @@
+package norm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNoChanges is returned by [Model.UpdateChanged] when no field of the
+// bound struct differs from its [Model.Snapshot].
+var ErrNoChanges = errors.New("norm: no changed fields to update")
+
+// Snapshot records the current field values of the bound struct. Later calls
+// to [Model.Changed] and [Model.UpdateChanged] compare against it.
+// Struct, map and slice fields are compared by their marshaled JSON bytes.
+//
+// Call Snapshot right after scanning a row, and again after a successful
+// update to start tracking from the new state.
+//
+//	_ = row.Scan(m.Pointers()...)
+//	_ = m.Snapshot()
+//	user.Name = "Bob"
+//	sql, args, _ := m.UpdateChanged(norm.Where("id = ?", user.Id))
+//	// "UPDATE users SET name=$1 WHERE id = $2"
+func (m *Model) Snapshot() error {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	snapshot := make(map[string]any, len(m.fields))
+	for _, f := range m.fields {
+		v, err := m.snapshotValue(f)
+		if err != nil {
+			return fmt.Errorf("Snapshot: %w", err)
+		}
+		snapshot[f.name] = v
+	}
+	m.snapshot = snapshot
+
+	return nil
+}
+
+// Changed returns the db names of fields whose values differ from the last
+// [Model.Snapshot]. Without a snapshot every field is reported as changed.
+//
+//	m.Changed() // ["name", "email"]
+func (m *Model) Changed() []string {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	ff := m.changedFields(m.fields)
+
+	res := make([]string, 0, len(ff))
+	for _, f := range ff {
+		res = append(res, f.dbName)
+	}
+	return res
+}
+
+// UpdateChanged builds an UPDATE query like [Model.Update] but sets only the
+// fields changed since the last [Model.Snapshot]. Without a snapshot it
+// behaves exactly like Update. Returns [ErrNoChanges] if nothing changed.
+// Supports [Exclude], [Fields], [Where], and [Returning] options.
+//
+//	sql, args, err := m.UpdateChanged(norm.Exclude("id"), norm.Where("id = ?", user.Id))
+//	if errors.Is(err, norm.ErrNoChanges) {
+//	    return nil
+//	}
+func (m *Model) UpdateChanged(opts ...Option) (string, []any, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	ff, co, err := m.filteredFields("UpdateChanged", opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ff = m.changedFields(ff)
+	if len(ff) == 0 {
+		return "", nil, ErrNoChanges
+	}
+
+	return m.updateSQL("UpdateChanged", ff, co)
+}
+
+// changedFields returns the subset of ff whose values differ from the
+// snapshot. Returns ff unchanged if there is no snapshot.
+// Must be called under m.mut.RLock.
+func (m *Model) changedFields(ff []*Field) []*Field {
+	if m.snapshot == nil {
+		return ff
+	}
+
+	res := make([]*Field, 0, len(ff))
+	for _, f := range ff {
+		cur, err := m.snapshotValue(f)
+		if err != nil || !reflect.DeepEqual(cur, m.snapshot[f.name]) {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// snapshotValue returns a comparable copy of the field's current value.
+// Struct, map and slice fields are marshaled to JSON so that in-place
+// modifications are detected; pointers are dereferenced.
+func (m *Model) snapshotValue(f *Field) (any, error) {
+	v := m.val.FieldByName(f.name)
+
+	kind := indirectType(f.valType).Kind()
+	if f.IsJSON() || kind == reflect.Map || kind == reflect.Slice {
+		b, err := m.config.JSONMarshal(v.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("json marshal field %q: %w", f.name, err)
+		}
+		return b, nil
+	}
+
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface(), nil
+}
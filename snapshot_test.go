@@ -0,0 +1,136 @@
+package norm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type SnapshotTestStruct struct {
+	Id      int `norm:"pk"`
+	Name    string
+	Nick    *string
+	Tags    []string
+	Address JSONAddress
+}
+
+func TestSnapshot(t *testing.T) {
+	n := NewNorm(nil)
+
+	t.Run("no snapshot reports all fields", func(t *testing.T) {
+		m, _ := n.M(&SnapshotTestStruct{})
+		got := m.Changed()
+		want := []string{"id", "name", "nick", "tags", "address"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		m, _ := n.M(&SnapshotTestStruct{Id: 1, Name: "Alice"})
+		if err := m.Snapshot(); err != nil {
+			t.Fatal(err)
+		}
+		if got := m.Changed(); len(got) != 0 {
+			t.Errorf("expected no changes, got %v", got)
+		}
+	})
+
+	t.Run("detects scalar, pointer, slice and json changes", func(t *testing.T) {
+		nick := "al"
+		obj := &SnapshotTestStruct{Id: 1, Name: "Alice", Nick: &nick, Tags: []string{"a"}}
+		m, _ := n.M(obj)
+		if err := m.Snapshot(); err != nil {
+			t.Fatal(err)
+		}
+
+		obj.Name = "Bob"
+		*obj.Nick = "bob"
+		obj.Tags[0] = "b"
+		obj.Address.City = "Paris"
+
+		got := m.Changed()
+		want := []string{"name", "nick", "tags", "address"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("nil pointer set", func(t *testing.T) {
+		obj := &SnapshotTestStruct{Id: 1}
+		m, _ := n.M(obj)
+		_ = m.Snapshot()
+
+		empty := ""
+		obj.Nick = &empty
+
+		got := m.Changed()
+		if !reflect.DeepEqual(got, []string{"nick"}) {
+			t.Errorf("got %v", got)
+		}
+	})
+}
+
+func TestUpdateChanged(t *testing.T) {
+	n := NewNorm(nil)
+
+	t.Run("updates only changed fields", func(t *testing.T) {
+		obj := &ModelTestStruct{Id: 1, Name: "Alice", Email: "alice@test.com", Age: 25}
+		m, _ := n.M(obj)
+		_ = m.Snapshot()
+
+		obj.Email = "new@test.com"
+		obj.Age = 26
+
+		sql, args, err := m.UpdateChanged(Where("id = ?", obj.Id), Returning("Id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "UPDATE model_test_struct SET email=$1, age=$2 WHERE id = $3 RETURNING id"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 3 || args[0] != "new@test.com" || args[1] != 26 || args[2] != 1 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("respects exclude", func(t *testing.T) {
+		obj := &ModelTestStruct{Id: 1, Name: "Alice"}
+		m, _ := n.M(obj)
+		_ = m.Snapshot()
+
+		obj.Id = 2
+		obj.Name = "Bob"
+
+		sql, _, err := m.UpdateChanged(Exclude("id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql != "UPDATE model_test_struct SET name=$1" {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		m, _ := n.M(&ModelTestStruct{Id: 1})
+		_ = m.Snapshot()
+
+		_, _, err := m.UpdateChanged(Where("id = ?", 1))
+		if !errors.Is(err, ErrNoChanges) {
+			t.Errorf("expected ErrNoChanges, got %v", err)
+		}
+	})
+
+	t.Run("without snapshot behaves like update", func(t *testing.T) {
+		m, _ := n.M(&ModelTestStruct{Id: 1})
+		got, _, err := m.UpdateChanged(Exclude("id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _, _ := m.Update(Exclude("id"))
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
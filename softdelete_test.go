@@ -0,0 +1,209 @@
+package norm
+
+import (
+	"testing"
+	"time"
+)
+
+type SoftUser struct {
+	Id        int `norm:"pk"`
+	Name      string
+	DeletedAt *time.Time `norm:"softdelete"`
+}
+
+type SoftOrder struct {
+	Id        int        `norm:"pk"`
+	UserId    int        `norm:"fk=SoftUser"`
+	DeletedAt *time.Time `norm:"softdelete"`
+}
+
+func newSoftModels(t *testing.T) (*Model, *Model) {
+	t.Helper()
+	n := NewNorm(nil)
+	mUser, err := n.M(&SoftUser{Id: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mOrder, err := n.M(&SoftOrder{Id: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mUser, mOrder
+}
+
+func TestSoftDeleteSelect(t *testing.T) {
+	m, _ := newSoftModels(t)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default filter", nil,
+			"SELECT id, name, deleted_at FROM soft_user WHERE deleted_at IS NULL"},
+		{"combined with where", []Option{Where("name = ? OR id = ?", "a", 1)},
+			"SELECT id, name, deleted_at FROM soft_user WHERE (name = $1 OR id = $2) AND deleted_at IS NULL"},
+		{"with deleted", []Option{WithDeleted()},
+			"SELECT id, name, deleted_at FROM soft_user"},
+		{"only deleted", []Option{OnlyDeleted()},
+			"SELECT id, name, deleted_at FROM soft_user WHERE deleted_at IS NOT NULL"},
+		{"with prefix", []Option{Prefix("u.")},
+			"SELECT u.id, u.name, u.deleted_at FROM soft_user WHERE u.deleted_at IS NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := m.Select(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.want {
+				t.Errorf("got:\n  %q\nwant:\n  %q", sql, tt.want)
+			}
+		})
+	}
+
+	t.Run("select by pk", func(t *testing.T) {
+		sql, _, _ := m.SelectByPK()
+		want := "SELECT id, name, deleted_at FROM soft_user WHERE id=$1 AND deleted_at IS NULL"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("exists by pk", func(t *testing.T) {
+		sql, _, _ := m.ExistsByPK()
+		want := "SELECT EXISTS (SELECT 1 FROM soft_user WHERE id=$1 AND deleted_at IS NULL)"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+}
+
+func TestSoftDeleteDelete(t *testing.T) {
+	m, _ := newSoftModels(t)
+
+	t.Run("soft delete", func(t *testing.T) {
+		sql, args, err := m.Delete(Where("id = ?", 1), Returning("Id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "UPDATE soft_user SET deleted_at=now() WHERE (id = $1) AND deleted_at IS NULL RETURNING id"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 1 || args[0] != 1 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("soft delete by pk", func(t *testing.T) {
+		sql, _, _ := m.DeleteByPK()
+		want := "UPDATE soft_user SET deleted_at=now() WHERE id=$1 AND deleted_at IS NULL"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("hard delete", func(t *testing.T) {
+		sql, _, _ := m.Delete(HardDelete(), Where("id = ?", 1))
+		if sql != "DELETE FROM soft_user WHERE id = $1" {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("hard delete only deleted", func(t *testing.T) {
+		sql, _, _ := m.Delete(HardDelete(), OnlyDeleted())
+		if sql != "DELETE FROM soft_user WHERE deleted_at IS NOT NULL" {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("model without softdelete", func(t *testing.T) {
+		mt := newTestModel()
+		sql, _, _ := mt.Delete(Where("id = ?", 1))
+		if sql != "DELETE FROM model_test_struct WHERE id = $1" {
+			t.Errorf("got %q", sql)
+		}
+	})
+}
+
+func TestSoftDeleteBuildConditions(t *testing.T) {
+	m, _ := newSoftModels(t)
+
+	t.Run("default filter", func(t *testing.T) {
+		conds, vals := m.BuildConditions(Eq("name", "John"))
+		if len(conds) != 2 || conds[0] != "name=$1" || conds[1] != "deleted_at IS NULL" {
+			t.Errorf("unexpected conds: %v", conds)
+		}
+		if len(vals) != 1 {
+			t.Errorf("unexpected vals: %v", vals)
+		}
+	})
+
+	t.Run("with prefix", func(t *testing.T) {
+		conds, _ := m.BuildConditions(Prefix("u."))
+		if len(conds) != 1 || conds[0] != "u.deleted_at IS NULL" {
+			t.Errorf("unexpected conds: %v", conds)
+		}
+	})
+
+	t.Run("with deleted", func(t *testing.T) {
+		conds, _ := m.BuildConditions(Eq("name", "John"), WithDeleted())
+		if len(conds) != 1 {
+			t.Errorf("unexpected conds: %v", conds)
+		}
+	})
+
+	t.Run("only deleted", func(t *testing.T) {
+		conds, _ := m.BuildConditions(OnlyDeleted())
+		if len(conds) != 1 || conds[0] != "deleted_at IS NOT NULL" {
+			t.Errorf("unexpected conds: %v", conds)
+		}
+	})
+}
+
+func TestSoftDeleteJoin(t *testing.T) {
+	mUser, mOrder := newSoftModels(t)
+
+	t.Run("filters base in where and joined in on", func(t *testing.T) {
+		sql, args, err := NewJoin(mUser).
+			AutoLeft(mOrder).
+			Where("soft_user.name = ?", "Alice").
+			Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT soft_user.id, soft_user.name, soft_user.deleted_at, soft_order.id, soft_order.user_id, soft_order.deleted_at FROM soft_user" +
+			" LEFT JOIN soft_order ON (soft_order.user_id = soft_user.id) AND soft_order.deleted_at IS NULL" +
+			" WHERE (soft_user.name = $1) AND soft_user.deleted_at IS NULL"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 1 || args[0] != "Alice" {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("with deleted", func(t *testing.T) {
+		sql, _, _ := NewJoin(mUser).Auto(mOrder).WithDeleted().Select()
+		want := "SELECT soft_user.id, soft_user.name, soft_user.deleted_at, soft_order.id, soft_order.user_id, soft_order.deleted_at FROM soft_user" +
+			" INNER JOIN soft_order ON soft_order.user_id = soft_user.id"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+}
+
+func TestSoftDeleteMultipleFieldsPanics(t *testing.T) {
+	type Bad struct {
+		A *time.Time `norm:"softdelete"`
+		B *time.Time `norm:"softdelete"`
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for multiple softdelete fields")
+		}
+	}()
+	NewNorm(nil).M(&Bad{})
+}
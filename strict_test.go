@@ -0,0 +1,146 @@
+package norm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFieldsAnyNameFormat(t *testing.T) {
+	m := newTestModel()
+
+	tests := []struct {
+		name string
+		opt  Option
+		want string
+	}{
+		{"fields go names", Fields("Name, Email"), "name, email"},
+		{"fields camelCase", Fields("name,email"), "name, email"},
+		{"exclude go names", Exclude("Id, Age"), "name, email"},
+		{"exclude unknown ignored", Exclude("id,nope"), "name, email, age"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Fields(tt.opt); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	n := NewNorm(&Config{Strict: true})
+	m, _ := n.M(&ModelTestStruct{Id: 1, Name: "Alice"})
+
+	t.Run("select unknown field in Fields", func(t *testing.T) {
+		_, _, err := m.Select(Fields("name,nmae"))
+		if !errors.Is(err, ErrUnknownField) {
+			t.Fatalf("expected ErrUnknownField, got %v", err)
+		}
+		want := `Select: unknown field "nmae" in model "model_test_struct"`
+		if err.Error() != want {
+			t.Errorf("got %q", err.Error())
+		}
+	})
+
+	t.Run("update unknown field in Exclude", func(t *testing.T) {
+		_, _, err := m.Update(Exclude("Idd"))
+		if !errors.Is(err, ErrUnknownField) {
+			t.Fatalf("expected ErrUnknownField, got %v", err)
+		}
+	})
+
+	t.Run("insert on conflict update list", func(t *testing.T) {
+		_, _, err := m.Insert(OnConflict("Id").DoUpdate(Exclude("nope")))
+		if !errors.Is(err, ErrUnknownField) {
+			t.Fatalf("expected ErrUnknownField, got %v", err)
+		}
+	})
+
+	t.Run("where conds", func(t *testing.T) {
+		_, _, err := m.Delete(WhereConds(Eq("id", 1), Or(Eq("nmae", "x"), Eq("age", 2))))
+		if !errors.Is(err, ErrUnknownField) {
+			t.Fatalf("expected ErrUnknownField, got %v", err)
+		}
+		if !strings.Contains(err.Error(), `"nmae"`) {
+			t.Errorf("got %q", err.Error())
+		}
+	})
+
+	t.Run("known names pass", func(t *testing.T) {
+		sql, _, err := m.Select(Fields("Id, name"), WhereConds(Eq("Name", "Alice")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id, name FROM model_test_struct WHERE name=$1"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("build conditions panics", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic")
+			}
+		}()
+		m.BuildConditions(Eq("nmae", "x"))
+	})
+
+	t.Run("pointers panics", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic")
+			}
+		}()
+		m.Pointers(Fields("nmae"))
+	})
+
+	t.Run("join where conds", func(t *testing.T) {
+		mu, _ := n.M(&JoinUser{})
+		mo, _ := n.M(&JoinOrder{})
+		_, _, err := NewJoin(mu).
+			Inner(mo, "join_order.user_id = join_user.id").
+			WhereConds(Eq("orders.Total", 1)).
+			Select()
+		if !errors.Is(err, ErrUnknownField) {
+			t.Fatalf("expected ErrUnknownField, got %v", err)
+		}
+	})
+}
+
+func TestBuildConditionsE(t *testing.T) {
+	m := newCondTestModel()
+
+	t.Run("ok", func(t *testing.T) {
+		conds, vals, err := m.BuildConditionsE(Eq("Name", "x"), Gt("age", 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conds) != 2 || conds[0] != "name=$1" || conds[1] != "age > $2" {
+			t.Errorf("got %v", conds)
+		}
+		if len(vals) != 2 {
+			t.Errorf("expected 2 vals, got %d", len(vals))
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, _, err := m.BuildConditionsE(Eq("name", "x"), Eq("u.nope", 1))
+		if !errors.Is(err, ErrUnknownField) {
+			t.Fatalf("expected ErrUnknownField, got %v", err)
+		}
+		want := `BuildConditionsE: unknown field "u.nope" in model "cond_test_struct"`
+		if err.Error() != want {
+			t.Errorf("got %q", err.Error())
+		}
+	})
+
+	t.Run("non-strict BuildConditions skips", func(t *testing.T) {
+		conds, _ := m.BuildConditions(Eq("nope", 1))
+		if len(conds) != 0 {
+			t.Errorf("got %v", conds)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package norm
+
+// condSub represents field [NOT] IN (subquery).
+type condSub struct {
+	field string
+	sub   Query
+	not   bool
+}
+
+func (c condSub) isCond() {}
+
+// condExists represents EXISTS (subquery).
+type condExists struct {
+	sub Query
+}
+
+func (c condExists) isCond() {}
+
+// SubSelect builds a SELECT like [Model.Select] for use inside another
+// statement with [InSub], [Exists], [With] or [Union]. Its placeholders are renumbered to
+// follow the binds of the outer statement when that is built, and a build
+// error is returned by the outer statement's builder.
+//
+//	sub := mOrder.SubSelect(norm.Fields("user_id"), norm.Where("total > ?", 100))
+//	sql, args, _ := mUser.Select(norm.Where("active = ?", true), norm.WhereConds(norm.InSub("Id", sub)))
+//	// "SELECT ... FROM users WHERE (active = $1) AND id IN (SELECT user_id FROM orders WHERE total > $2)"
+func (m *Model) SubSelect(opts ...Option) Query {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	ff, co, err := m.filteredFields("SubSelect", opts...)
+	if err != nil {
+		return Query{err: err}
+	}
+
+	q := NewQuery(m.selectSQL(ff, co))
+	q.dialect = m.config.Dialect
+	q.cols = len(ff) + len(co.Aggs)
+	if co.Total != nil {
+		q.cols++
+	}
+	return q
+}
+
+// InSub creates a field IN (subquery) condition. sub is usually built with
+// [Model.SubSelect] but any [Query] in the dialect of the outer statement
+// works.
+//
+//	norm.InSub("UserId", mOrder.SubSelect(norm.Fields("user_id")))
+//	// user_id IN (SELECT user_id FROM orders)
+func InSub(field string, sub Query) Cond {
+	return condSub{field: field, sub: sub}
+}
+
+// NotInSub creates a field NOT IN (subquery) condition.
+//
+//	norm.NotInSub("Id", mBan.SubSelect(norm.Fields("user_id")))
+//	// id NOT IN (SELECT user_id FROM bans)
+func NotInSub(field string, sub Query) Cond {
+	return condSub{field: field, sub: sub, not: true}
+}
+
+// Exists creates an EXISTS (subquery) condition. Use [Not] for NOT EXISTS.
+// Correlate the subquery with the outer table in its WHERE template.
+//
+//	norm.Exists(mOrder.SubSelect(norm.Fields("id"), norm.Where("orders.user_id = users.id")))
+//	// EXISTS (SELECT id FROM orders WHERE orders.user_id = users.id)
+func Exists(sub Query) Cond {
+	return condExists{sub: sub}
+}
+
+// subqueryErr returns the first build error of a subquery used in conds.
+func subqueryErr(conds []Cond) error {
+	for _, c := range conds {
+		var err error
+		switch v := c.(type) {
+		case condSub:
+			err = v.sub.err
+		case condExists:
+			err = v.sub.err
+		case condGroup:
+			err = subqueryErr(v.conds)
+		case condNot:
+			err = subqueryErr([]Cond{v.cond})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package norm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSubSelect(t *testing.T) {
+	mUser, mOrder, _ := setupJoinModels(t)
+
+	t.Run("in subquery renumbered", func(t *testing.T) {
+		sub := mOrder.SubSelect(Fields("user_id"), Where("total > ?", 100))
+		sql, args, err := mUser.Select(
+			Fields("id"),
+			Where("name = ? AND email <> ?", "Alice", ""),
+			WhereConds(InSub("Id", sub)),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM join_user WHERE (name = $1 AND email <> $2) AND id IN (SELECT user_id FROM join_order WHERE total > $3)"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 3 || args[2] != 100 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("exists and not in", func(t *testing.T) {
+		exists := mOrder.SubSelect(Fields("id"), Where("join_order.user_id = join_user.id AND total > ?", 5))
+		banned := mOrder.SubSelect(Fields("user_id"), Where("total < ?", 0))
+		conds, args := mUser.BuildConditions(Eq("Name", "Bob"), Exists(exists), NotInSub("Id", banned))
+		want := []string{
+			"name=$1",
+			"EXISTS (SELECT id FROM join_order WHERE join_order.user_id = join_user.id AND total > $2)",
+			"id NOT IN (SELECT user_id FROM join_order WHERE total < $3)",
+		}
+		if len(conds) != len(want) {
+			t.Fatalf("got %q", conds)
+		}
+		for i := range want {
+			if conds[i] != want[i] {
+				t.Errorf("got %q, want %q", conds[i], want[i])
+			}
+		}
+		if len(args) != 3 || args[1] != 5 || args[2] != 0 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("not exists in join", func(t *testing.T) {
+		sub := mOrder.SubSelect(Fields("id"), Where("join_order.user_id = join_user.id"))
+		sql, _, err := NewJoin(mUser).Where("join_user.name = ?", "Alice").WhereConds(Not(Exists(sub))).Select()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT join_user.id, join_user.name, join_user.email FROM join_user WHERE (join_user.name = $1) AND NOT (EXISTS (SELECT id FROM join_order WHERE join_order.user_id = join_user.id))"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+
+	t.Run("build error", func(t *testing.T) {
+		n := NewNorm(&Config{Strict: true})
+		m, _ := n.M(&JoinOrder{})
+		sub := m.SubSelect(Fields("nope"))
+		_, _, err := mUser.Select(WhereConds(InSub("Id", sub)))
+		if !errors.Is(err, ErrUnknownField) {
+			t.Errorf("expected ErrUnknownField, got %v", err)
+		}
+	})
+}
+
+func TestBinderEmbed(t *testing.T) {
+	b := newBinder(1)
+	b.bind("outer")
+
+	got := b.embed(Query{
+		SQL:  "SELECT 1 WHERE a = $1 AND b = $2 AND c = $1 AND d = '$1'",
+		Args: []any{"x", "y"},
+	})
+	want := "SELECT 1 WHERE a = $2 AND b = $3 AND c = $2 AND d = '$1'"
+	if got != want {
+		t.Errorf("got:\n  %q\nwant:\n  %q", got, want)
+	}
+	if len(b.args) != 3 || b.args[1] != "x" || b.args[2] != "y" {
+		t.Errorf("unexpected args: %v", b.args)
+	}
+}
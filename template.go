@@ -0,0 +1,107 @@
+package norm
+
+import "strings"
+
+// skipQuoted returns the end (exclusive) of a quoted literal, quoted
+// identifier, dollar-quoted string or comment starting at t[i], or i if
+// none starts there. Placeholders inside these are not substituted.
+// Unterminated tokens extend to the end of the template.
+//
+//	'it''s ?'    string literal, '' is an escaped quote
+//	E'it\'s ?'   escape string, backslash escapes the next byte
+//	"col?"       quoted identifier
+//	$$ ? $$      dollar-quoted string, also with a tag: $fn$ ? $fn$
+//	-- ?         line comment
+//	/* ? */      block comment, may be nested
+func skipQuoted(t string, i int) int {
+	switch c := t[i]; {
+	case c == '\'':
+		escapes := i > 0 && (t[i-1] == 'E' || t[i-1] == 'e') && (i < 2 || !isNameByte(t[i-2], false))
+		return skipString(t, i, '\'', escapes)
+
+	case c == '"':
+		return skipString(t, i, '"', false)
+
+	case c == '-' && strings.HasPrefix(t[i:], "--"):
+		if end := strings.IndexByte(t[i:], '\n'); end >= 0 {
+			return i + end + 1
+		}
+		return len(t)
+
+	case c == '/' && strings.HasPrefix(t[i:], "/*"):
+		depth := 0
+		for j := i; j < len(t)-1; j++ {
+			switch t[j : j+2] {
+			case "/*":
+				depth++
+				j++
+			case "*/":
+				depth--
+				j++
+				if depth == 0 {
+					return j + 1
+				}
+			}
+		}
+		return len(t)
+
+	case c == '$' && (i == 0 || !isNameByte(t[i-1], false)):
+		tag, ok := dollarTag(t, i)
+		if !ok {
+			return i
+		}
+		if end := strings.Index(t[i+len(tag):], tag); end >= 0 {
+			return i + len(tag) + end + len(tag)
+		}
+		return len(t)
+	}
+
+	return i
+}
+
+// skipString returns the end of a string quoted with q starting at t[i].
+// A doubled quote is an escaped quote; with escapes set a backslash also
+// escapes the next byte.
+func skipString(t string, i int, q byte, escapes bool) int {
+	for j := i + 1; j < len(t); j++ {
+		switch t[j] {
+		case '\\':
+			if escapes {
+				j++
+			}
+		case q:
+			if j+1 < len(t) && t[j+1] == q {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(t)
+}
+
+// dollarTag returns the opening tag ("$$" or "$name$") of a dollar-quoted
+// string starting at t[i]. Positional parameters like $1 are not tags.
+func dollarTag(t string, i int) (string, bool) {
+	for j := i + 1; j < len(t); j++ {
+		switch {
+		case t[j] == '$':
+			return t[i : j+1], true
+		case !isNameByte(t[j], j == i+1):
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// isNameByte reports whether c can appear in a parameter name. Digits are
+// not allowed as the first character.
+func isNameByte(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
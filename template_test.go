@@ -0,0 +1,202 @@
+package norm
+
+import "testing"
+
+func TestWherePlaceholderTokenizer(t *testing.T) {
+	tests := []struct {
+		name  string
+		where string
+		want  string
+		next  int
+	}{
+		{"plain", "a = ? AND b = ?", "a = $1 AND b = $2", 3},
+		{"escaped question mark", "tags ?? ? AND note = 'why?'", "tags ? $1 AND note = 'why?'", 2},
+		{"jsonb operators", "tags ??| ? OR tags ??& ?", "tags ?| $1 OR tags ?& $2", 3},
+		{"doubled quote in literal", "a = 'it''s ?' AND b = ?", "a = 'it''s ?' AND b = $1", 2},
+		{"escape string", `a = E'\'?' AND b = ?`, `a = E'\'?' AND b = $1`, 2},
+		{"quoted identifier", `"who?" = ?`, `"who?" = $1`, 2},
+		{"dollar quoted", "a = $$ ? $$ AND b = $tag$ it's ? $tag$ AND c = ?", "a = $$ ? $$ AND b = $tag$ it's ? $tag$ AND c = $1", 2},
+		{"line comment", "a = ? -- really?\nAND b = ?", "a = $1 -- really?\nAND b = $2", 3},
+		{"block comment", "a = ? /* x /* ? */ ? */ AND b = ?", "a = $1 /* x /* ? */ ? */ AND b = $2", 3},
+		{"unterminated literal", "a = ? AND b = 'x?", "a = $1 AND b = 'x?", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next := parseWhere(tt.where).Build(1)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if next != tt.next {
+				t.Errorf("next bind: got %d, want %d", next, tt.next)
+			}
+		})
+	}
+}
+
+func TestWhereNamedTokenizer(t *testing.T) {
+	m := newTestModel()
+	sql, args, err := m.Select(Fields("id"), WhereNamed(
+		"name = :name AND email <> 'x:name' /* :name */ AND data ? 'k'",
+		map[string]any{"name": "Bob"},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT id FROM model_test_struct WHERE name = $1 AND email <> 'x:name' /* :name */ AND data ? 'k'"
+	if sql != want {
+		t.Errorf("got %q", sql)
+	}
+	if len(args) != 1 {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestWhereSliceExpansion(t *testing.T) {
+	m := newTestModel()
+
+	t.Run("expand and shift", func(t *testing.T) {
+		sql, args, err := m.Select(Fields("id"), Where("id IN (?) AND name = ?", []int{1, 2, 3}, "active"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE id IN ($1, $2, $3) AND name = $4"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 4 || args[0] != 1 || args[2] != 3 || args[3] != "active" {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("update after set", func(t *testing.T) {
+		sql, args, err := m.Update(Fields("name"), Where("id IN (?)", []string{"a", "b"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "UPDATE model_test_struct SET name=$1 WHERE id IN ($2, $3)"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 3 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("empty slice", func(t *testing.T) {
+		sql, args, err := m.Select(Fields("id"), Where("id IN (?)", []int{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE id IN (NULL)"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 0 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("empty slice in NOT IN", func(t *testing.T) {
+		tests := []Option{
+			Where("id NOT IN (?)", []int{}),
+			Where("id not in(?)", []int{}),
+			WhereNamed("id IN (:ids) OR age NOT IN (:ids)", map[string]any{"ids": []int{}}),
+		}
+		for _, opt := range tests {
+			if _, _, err := m.Select(Fields("id"), opt); err == nil {
+				t.Error("expected error")
+			}
+		}
+
+		// NotIn matches every row for an empty list
+		sql, _, err := m.Select(Fields("id"), WhereConds(NotIn("id")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql != "SELECT id FROM model_test_struct WHERE TRUE" {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("array operand", func(t *testing.T) {
+		tests := []struct {
+			opt  Option
+			want string
+		}{
+			{Where("id = ANY(?) AND age > ?", []int{1, 2}, 3), "SELECT id FROM model_test_struct WHERE id = ANY($1) AND age > $2"},
+			{Where("age <> all (?)", []int{1, 2}), "SELECT id FROM model_test_struct WHERE age <> all ($1)"},
+			{Where("id=ANY(?)", []int{}), "SELECT id FROM model_test_struct WHERE id=ANY($1)"},
+			{WhereNamed("id = ANY(:ids)", map[string]any{"ids": []int{1, 2}}), "SELECT id FROM model_test_struct WHERE id = ANY($1)"},
+		}
+		for _, tt := range tests {
+			sql, args, err := m.Select(Fields("id"), tt.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.want {
+				t.Errorf("got:\n  %q\nwant:\n  %q", sql, tt.want)
+			}
+			if _, ok := args[0].([]int); !ok {
+				t.Errorf("expected []int arg, got %#v", args[0])
+			}
+		}
+	})
+
+	t.Run("bytes and Array not expanded", func(t *testing.T) {
+		sql, args, err := m.Select(Fields("id"), Where("data = ? AND id = ANY(?)", []byte("x"), Array([]int{1, 2})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE data = $1 AND id = ANY($2)"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 2 {
+			t.Fatalf("unexpected args: %v", args)
+		}
+		if ids, ok := args[1].([]int); !ok || len(ids) != 2 {
+			t.Errorf("expected []int arg, got %#v", args[1])
+		}
+	})
+
+	t.Run("named", func(t *testing.T) {
+		sql, args, err := m.Select(Fields("id"), WhereNamed("id IN (:ids) OR age IN (:ids)", map[string]any{"ids": []int{4, 5}}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE id IN ($1, $2) OR age IN ($1, $2)"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 2 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("BuildWhere", func(t *testing.T) {
+		where, args := BuildWhere(3, "id IN (?) AND age > ?", []int{1, 2}, 18)
+		if where != "id IN ($3, $4) AND age > $5" {
+			t.Errorf("got %q", where)
+		}
+		if len(args) != 3 || args[2] != 18 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("slices as arrays", func(t *testing.T) {
+		n := NewNorm(&Config{SlicesAsArrays: true})
+		ma, _ := n.M(&ModelTestStruct{})
+		sql, args, err := ma.Select(Fields("id"), Where("id = ANY(?) AND name = ?", []int{1, 2, 3}, "x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "SELECT id FROM model_test_struct WHERE id = ANY($1) AND name = $2"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+		if len(args) != 2 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package norm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrVersionConflict reports that an optimistic-locking UPDATE matched no
+// rows: the row was changed (or deleted) by someone else since it was read.
+// See [CheckVersion].
+var ErrVersionConflict = errors.New("norm: version conflict, row was modified concurrently")
+
+// CheckVersion returns [ErrVersionConflict] if an UPDATE of a model with a
+// version field affected no rows. Pass the driver's rows-affected count.
+//
+// For models with a field tagged `norm:"version"`, [Model.Update] and its
+// variants add "AND version=$n" with the bound struct's current value,
+// render "version=version+1" in SET, and append the version column to
+// RETURNING so the struct can be refreshed:
+//
+//	sql, args, _ := m.UpdateByPK()
+//	// "UPDATE docs SET title=$1, version=version+1 WHERE id=$2 AND version=$3 RETURNING version"
+//	tag, err := pool.Exec(ctx, sql, args...)
+//	if err == nil {
+//	    err = norm.CheckVersion(tag.RowsAffected())
+//	}
+//
+// When scanning the RETURNING row instead, a "no rows" error from the
+// driver means the same thing.
+// Without RETURNING support, as in [MySQL], the column is not appended;
+// the new version is the old one plus one.
+func CheckVersion(rowsAffected int64) error {
+	if rowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// versionWhere returns the optimistic-locking predicate for the bound
+// struct's current version, or nil if the model has no version field.
+// Must be called under m.mut.RLock.
+func (m *Model) versionWhere(method string) (*whereOption, error) {
+	if m.version == "" {
+		return nil, nil
+	}
+	f := m.fieldByAnyName[m.version]
+	val, err := m.fieldValue(m.val, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	return &whereOption{template: m.ident(f.dbName) + "=?", Args: []any{val}, grouped: true}, nil
+}
+
+// withVersion appends the version column to a RETURNING list unless it is
+// already there or the list returns all columns ("*"). Returns returning
+// unchanged if the model has no version field or the dialect has no
+// RETURNING, see [FeatureReturning].
+// Must be called under m.mut.RLock.
+func (m *modelMeta) withVersion(returning []string) []string {
+	if m.version == "" || !m.config.Dialect.Supports(FeatureReturning) {
+		return returning
+	}
+	for _, name := range returning {
+		name = strings.TrimSpace(name)
+		if name == "*" {
+			return returning
+		}
+		if f, ok := m.fieldByAnyName[name]; ok && f.dbName == m.version {
+			return returning
+		}
+	}
+	res := make([]string, 0, len(returning)+1)
+	res = append(res, returning...)
+	return append(res, m.version)
+}
@@ -0,0 +1,127 @@
+package norm
+
+import (
+	"errors"
+	"testing"
+)
+
+type VersionedDoc struct {
+	Id      int `norm:"pk"`
+	Title   string
+	Version int `norm:"version"`
+}
+
+func TestVersionUpdate(t *testing.T) {
+	n := NewNorm(nil)
+	m, _ := n.M(&VersionedDoc{Id: 1, Title: "Draft", Version: 3})
+
+	t.Run("update by pk", func(t *testing.T) {
+		sql, args, err := m.UpdateByPK()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "UPDATE versioned_doc SET title=$1, version=version+1 WHERE id=$2 AND version=$3 RETURNING version"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+		if len(args) != 3 || args[0] != "Draft" || args[1] != 1 || args[2] != 3 {
+			t.Errorf("unexpected args: %v", args)
+		}
+	})
+
+	t.Run("update with where and returning", func(t *testing.T) {
+		sql, _, err := m.Update(Fields("title"), Where("id = ?", 1), Returning("Id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "UPDATE versioned_doc SET title=$1, version=version+1 WHERE (id = $2) AND version=$3 RETURNING id, version"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+
+	t.Run("version already in returning", func(t *testing.T) {
+		sql, _, _ := m.UpdateByPK(Returning("Version,Title"))
+		want := "UPDATE versioned_doc SET title=$1, version=version+1 WHERE id=$2 AND version=$3 RETURNING version, title"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("version bumped even when excluded", func(t *testing.T) {
+		sql, _, _ := m.UpdateByPK(Exclude("version"))
+		want := "UPDATE versioned_doc SET title=$1, version=version+1 WHERE id=$2 AND version=$3 RETURNING version"
+		if sql != want {
+			t.Errorf("got %q", sql)
+		}
+	})
+
+	t.Run("upsert increments version", func(t *testing.T) {
+		sql, _, _ := m.Insert(OnConflict().DoUpdate())
+		want := "INSERT INTO versioned_doc (id, title, version) VALUES ($1, $2, $3)" +
+			" ON CONFLICT (id) DO UPDATE SET title=EXCLUDED.title, version=versioned_doc.version+1"
+		if sql != want {
+			t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+		}
+	})
+}
+
+func TestVersionUpdateChanged(t *testing.T) {
+	n := NewNorm(nil)
+	doc := &VersionedDoc{Id: 1, Title: "Draft", Version: 3}
+	m, _ := n.M(doc)
+	_ = m.Snapshot()
+
+	_, _, err := m.UpdateChanged(Where("id = ?", 1))
+	if !errors.Is(err, ErrNoChanges) {
+		t.Errorf("expected ErrNoChanges, got %v", err)
+	}
+
+	doc.Title = "Final"
+	sql, _, err := m.UpdateChanged(Where("id = ?", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "UPDATE versioned_doc SET title=$1, version=version+1 WHERE (id = $2) AND version=$3 RETURNING version"
+	if sql != want {
+		t.Errorf("got %q", sql)
+	}
+}
+
+func TestVersionMySQL(t *testing.T) {
+	n := NewNorm(&Config{Dialect: MySQL})
+	doc := &VersionedDoc{Id: 1, Title: "Draft", Version: 3}
+	m, _ := n.M(doc)
+
+	sql, _, err := m.UpdateByPK()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "UPDATE versioned_doc SET title=?, version=version+1 WHERE id=? AND version=?"
+	if sql != want {
+		t.Errorf("got:\n  %q\nwant:\n  %q", sql, want)
+	}
+
+	if _, _, err := m.Update(Where("id = ?", 1)); err != nil {
+		t.Errorf("Update: %v", err)
+	}
+
+	_ = m.Snapshot()
+	doc.Title = "Final"
+	if _, _, err := m.UpdateChanged(Where("id = ?", 1)); err != nil {
+		t.Errorf("UpdateChanged: %v", err)
+	}
+
+	if _, _, err := m.UpdateByPK(Returning("Id")); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("explicit Returning: expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	if err := CheckVersion(0); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("expected ErrVersionConflict, got %v", err)
+	}
+	if err := CheckVersion(1); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}